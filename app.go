@@ -1,20 +1,35 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"rmount/config"
+	"rmount/keyring"
 	"rmount/rclone"
 	"rmount/s3"
+	"rmount/sftp"
 	gistsync "rmount/sync"
 	"rmount/system"
+	"rmount/webdav"
+
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/options"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// masterKeyAccount 派生密钥在本机密钥存储中的条目名
+const masterKeyAccount = "master-key"
+
 // App struct
 type App struct {
 	ctx context.Context
@@ -36,11 +51,117 @@ type App struct {
 	// 自启动管理
 	autoStartManager *system.AutoStartManager
 
-	// 挂载管理
+	// 本机密钥存储，用于记住主密码
+	keyStore keyring.KeyStore
+
+	// 挂载管理，按挂载ID记录
 	mountProcesses map[string]*rclone.MountInfo
+	// pendingMounts 正在执行rclone挂载的记录，用于检查冲突
+	pendingMounts map[string]*rclone.MountInfo
+	// mountEpoch 卸载所有挂载时递增，挂载完成时发现变化则取消该挂载
+	mountEpoch int
 	mountMutex     sync.RWMutex
+
+	// 最近一次用户操作的时间（UnixNano），用于空闲自动锁定
+	lastActivity atomic.Int64
+
+	// 自动挂载只在首次解锁后执行一次，之后手动卸载的挂载不会被重新挂载
+	autoMountOnce sync.Once
+
+	// 挂载监督，按挂载ID记录，由 mountMutex 保护
+	supervisors map[string]*mountSupervisor
+
+	// 正在实时查看的挂载日志，按挂载ID记录停止信号
+	logWatchers map[string]chan struct{}
+	logMutex    sync.Mutex
+}
+
+// EventConfigLocked 配置被锁定时发送给前端的事件，参数为锁定原因
+const EventConfigLocked = "config:locked"
+
+// EventConfigReloaded 配置文件被外部修改并重新加载后发送给前端的事件
+const EventConfigReloaded = "config:reloaded"
+
+// EventMountJob 自动挂载任务状态变化时发送给前端的事件，参数为 MountJobEvent
+const EventMountJob = "mount:job"
+
+// EventMountStatus 挂载失效、重新挂载或放弃重新挂载时发送给前端的事件，参数为 rclone.MountInfo
+const EventMountStatus = "mount:status"
+
+// 挂载监督参数
+const (
+	mountCheckInterval       = 10 * time.Second
+	mountRestartInitialDelay = 2 * time.Second
+	mountRestartMaxDelay     = time.Minute
+	// mountMaxRestartAttempts 连续重新挂载失败的次数上限，超过后放弃
+	mountMaxRestartAttempts = 6
+)
+
+// mountSupervisor 挂载的监督信息，重新挂载时使用挂载时的rclone参数，
+// 锁定后配置不可读时也能恢复挂载
+type mountSupervisor struct {
+	stop       chan struct{}
+	remoteName string
+	remotePath string
+	opts       config.MountOptions
+}
+
+// EventMountLog 实时查看的挂载日志有新内容时发送给前端的事件，参数为 MountLogEvent
+const EventMountLog = "mount:log"
+
+// 挂载日志参数
+const (
+	defaultMountLogLines = 200
+	maxMountLogLines     = 5000
+	mountLogPollInterval = time.Second
+)
+
+// MountLogEvent 挂载日志新增的行
+type MountLogEvent struct {
+	MountID string   `json:"mountId"`
+	Lines   []string `json:"lines"`
+}
+
+// 自动挂载任务参数
+const (
+	autoMountConcurrency   = 3
+	autoMountMaxAttempts   = 8
+	autoMountInitialDelay  = 2 * time.Second
+	autoMountMaxRetryDelay = time.Minute
+)
+
+// 自动挂载任务状态
+const (
+	mountJobMounting = "mounting"
+	mountJobRetrying = "retrying"
+	mountJobMounted  = "mounted"
+	mountJobFailed   = "failed"
+)
+
+// MountJobEvent 自动挂载任务的状态
+type MountJobEvent struct {
+	MountID  string `json:"mountId"`
+	SourceID string `json:"sourceId"`
+	Status   string `json:"status"`
+	Attempt  int    `json:"attempt"`
+	Error    string `json:"error,omitempty"`
+}
+
+// mountConflictError 挂载ID或路径已被占用，重试不会成功
+type mountConflictError string
+
+func (e mountConflictError) Error() string {
+	return string(e)
 }
 
+// 锁定原因
+const (
+	lockReasonManual = "manual"
+	lockReasonIdle   = "idle"
+	// lockReasonExternal 其他实例修改了主密码，当前密钥已无法解密配置
+	lockReasonExternal = "external"
+)
+
 // NewApp creates a new App application struct
 func NewApp() *App {
 	homeDir, _ := os.UserHomeDir()
@@ -49,6 +170,9 @@ func NewApp() *App {
 	return &App{
 		configDir:        configDir,
 		mountProcesses:   make(map[string]*rclone.MountInfo),
+		pendingMounts:    make(map[string]*rclone.MountInfo),
+		supervisors:      make(map[string]*mountSupervisor),
+		logWatchers:      make(map[string]chan struct{}),
 	}
 }
 
@@ -68,13 +192,51 @@ func (a *App) startup(ctx context.Context) {
 	appPath, _ := os.Executable()
 	a.autoStartManager = system.NewAutoStartManager("rmount", appPath)
 
+	// 初始化本机密钥存储，已注入时（例如测试）沿用
+	if a.keyStore == nil {
+		a.keyStore = keyring.New("rmount", a.configDir)
+	}
+
 	// 尝试加载配置
 	if err := a.loadOrCreateConfig(); err != nil {
 		fmt.Printf("加载配置失败: %v\n", err)
 	}
 
+	// 如果用户选择了记住密码，使用本机保存的密钥自动解锁
+	if err := a.unlockFromKeyStore(); err != nil {
+		fmt.Printf("自动解锁失败: %v\n", err)
+	}
+
+	// 已解锁时立即开始自动挂载，否则等待用户输入主密码
+	if a.IsPasswordSet() {
+		a.startAutoMount()
+	}
+
 	// 启动挂载状态检查
 	go a.monitorMountStatus()
+
+	// 启动空闲自动锁定检查
+	a.ReportActivity()
+	go a.monitorIdle()
+
+	// 监控配置文件的外部修改
+	go a.monitorConfigFile()
+}
+
+// shutdown 应用退出时停止对rclone rc服务的监督，rc服务及其挂载保持运行
+func (a *App) shutdown(ctx context.Context) {
+	if a.rcloneManager != nil {
+		a.rcloneManager.Close()
+	}
+}
+
+// onSecondInstanceLaunch 再次启动rmount时激活已运行实例的窗口
+func (a *App) onSecondInstanceLaunch(data options.SecondInstanceData) {
+	if a.ctx == nil {
+		return
+	}
+	runtime.WindowUnminimise(a.ctx)
+	runtime.WindowShow(a.ctx)
 }
 
 // loadOrCreateConfig 加载或创建配置
@@ -85,9 +247,10 @@ func (a *App) loadOrCreateConfig() error {
 		if err.Error() == "加密未初始化，无法解密配置" {
 			// 创建一个空配置，等待用户设置密码
 			a.appConfig = &config.AppConfig{
+				SchemaVersion:  config.CurrentSchemaVersion,
 				AutoStart:      false,
-				MountDirectory: filepath.Join(os.Getenv("HOME"), "mounts"),
-				S3DataSources:  []config.S3Config{},
+				MountDirectory: config.DefaultMountDirectory(),
+				DataSources:    []config.DataSource{},
 			}
 			return nil
 		}
@@ -97,8 +260,8 @@ func (a *App) loadOrCreateConfig() error {
 	a.appConfig = cfg
 
 	// 生成rclone配置文件
-	if len(cfg.S3DataSources) > 0 {
-		if err := a.rcloneManager.GenerateRcloneConfig(cfg.S3DataSources); err != nil {
+	if len(cfg.DataSources) > 0 {
+		if err := a.rcloneManager.GenerateRcloneConfig(cfg.DataSources); err != nil {
 			return fmt.Errorf("生成rclone配置失败: %v", err)
 		}
 	}
@@ -106,6 +269,37 @@ func (a *App) loadOrCreateConfig() error {
 	return nil
 }
 
+// unlockFromKeyStore 使用本机密钥存储中的派生密钥解锁配置
+func (a *App) unlockFromKeyStore() error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	key, err := a.keyStore.Get(masterKeyAccount)
+	if err == keyring.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	cm, err := config.NewConfigManagerWithKey(a.configDir, key)
+	if err == nil {
+		var cfg *config.AppConfig
+		cfg, err = cm.LoadConfig()
+		if err == nil {
+			a.configManager = cm
+			a.appConfig = cfg
+			return a.rcloneManager.GenerateRcloneConfig(cfg.DataSources)
+		}
+	}
+
+	// 保存的密钥已失效（例如在其他机器修改了密码），删除后等待用户输入密码
+	if errors.Is(err, config.ErrIncorrectPassword) {
+		a.keyStore.Delete(masterKeyAccount)
+	}
+	return err
+}
+
 // SetMasterPassword 设置主密码
 func (a *App) SetMasterPassword(password string) error {
 	a.configMutex.Lock()
@@ -117,303 +311,1517 @@ func (a *App) SetMasterPassword(password string) error {
 		return fmt.Errorf("设置主密码失败: %v", err)
 	}
 
-	// 尝试加载已存在的加密配置
+	// 尝试加载已存在的加密配置，失败时绝不覆盖磁盘上的配置
 	cfg, err := cm.LoadConfig()
 	if err != nil {
-		// 如果加载失败，使用当前配置
-		if a.appConfig != nil {
-			if err := cm.SaveConfig(a.appConfig); err != nil {
-				return fmt.Errorf("迁移配置失败: %v", err)
-			}
+		if errors.Is(err, config.ErrIncorrectPassword) {
+			return fmt.Errorf("主密码错误，请重新输入")
 		}
-	} else {
-		// 成功加载已存在的配置
-		a.appConfig = cfg
+		return fmt.Errorf("加载配置失败: %v", err)
+	}
 
-		// 生成rclone配置文件
-		if len(cfg.S3DataSources) > 0 {
-			if err := a.rcloneManager.GenerateRcloneConfig(cfg.S3DataSources); err != nil {
-				return fmt.Errorf("生成rclone配置失败: %v", err)
-			}
+	// 成功加载已存在的配置
+	a.appConfig = cfg
+
+	// 旧版XOR密钥加密的文件，使用新的密钥派生方式重新加密
+	if cm.NeedsMigration() {
+		if err := cm.SaveConfig(cfg); err != nil {
+			return fmt.Errorf("迁移配置失败: %v", err)
+		}
+	}
+
+	// 生成rclone配置文件
+	if len(cfg.DataSources) > 0 {
+		if err := a.rcloneManager.GenerateRcloneConfig(cfg.DataSources); err != nil {
+			return fmt.Errorf("生成rclone配置失败: %v", err)
 		}
 	}
 
 	a.configManager = cm
+	a.ReportActivity()
+	a.startAutoMount()
 	return nil
 }
 
-// IsPasswordSet 检查是否已设置主密码
-func (a *App) IsPasswordSet() bool {
-	a.configMutex.RLock()
-	defer a.configMutex.RUnlock()
+// ChangeMasterPassword 修改主密码并重新加密配置文件
+func (a *App) ChangeMasterPassword(oldPassword, newPassword string) error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
 
-	return a.configManager != nil && a.configManager.IsEncryptionInitialized()
+	if a.configManager == nil || !a.configManager.IsEncryptionInitialized() {
+		return fmt.Errorf("请先设置主密码")
+	}
+
+	if err := a.configManager.ChangePassword(oldPassword, newPassword); err != nil {
+		if errors.Is(err, config.ErrIncorrectPassword) {
+			return fmt.Errorf("原主密码错误")
+		}
+		return fmt.Errorf("修改主密码失败: %v", err)
+	}
+
+	// 已记住密码时同步更新本机保存的密钥
+	if remembered, err := a.keyStore.Has(masterKeyAccount); err == nil && remembered {
+		if err := a.keyStore.Set(masterKeyAccount, a.configManager.DerivedKey()); err != nil {
+			return fmt.Errorf("更新本机保存的密钥失败: %v", err)
+		}
+	}
+
+	return nil
 }
 
-// AddS3DataSource 添加S3数据源
-func (a *App) AddS3DataSource(name, endpoint, accessKey, secretKey, region, bucket, description string) error {
+// SetRememberPassword 设置是否在本机记住主密码
+func (a *App) SetRememberPassword(enabled bool) error {
 	a.configMutex.Lock()
 	defer a.configMutex.Unlock()
 
-	if a.appConfig == nil {
-		return fmt.Errorf("配置未初始化，请先设置主密码")
-	}
-
-	s3Config := config.S3Config{
-		ID:          generateID(),
-		Name:        name,
-		Endpoint:    endpoint,
-		AccessKey:   accessKey,
-		SecretKey:   secretKey,
-		Region:      region,
-		Bucket:      bucket,
-		Description: description,
+	if !enabled {
+		return a.keyStore.Delete(masterKeyAccount)
 	}
 
-	if err := a.configManager.AddS3DataSource(a.appConfig, s3Config); err != nil {
-		return err
+	if a.configManager == nil || !a.configManager.IsEncryptionInitialized() {
+		return fmt.Errorf("请先设置主密码")
 	}
 
+	// 确保配置文件已写入，保存的密钥才能与文件头中的盐对应
 	if err := a.configManager.SaveConfig(a.appConfig); err != nil {
 		return fmt.Errorf("保存配置失败: %v", err)
 	}
 
-	// 更新rclone配置
-	return a.rcloneManager.GenerateRcloneConfig(a.appConfig.S3DataSources)
+	return a.keyStore.Set(masterKeyAccount, a.configManager.DerivedKey())
 }
 
-// GetS3DataSources 获取S3数据源列表
-func (a *App) GetS3DataSources() ([]config.S3Config, error) {
+// IsPasswordRemembered 检查本机是否记住了主密码
+func (a *App) IsPasswordRemembered() bool {
+	remembered, err := a.keyStore.Has(masterKeyAccount)
+	return err == nil && remembered
+}
+
+// ListConfigSnapshots 获取配置快照列表
+func (a *App) ListConfigSnapshots() ([]config.Snapshot, error) {
 	a.configMutex.RLock()
 	defer a.configMutex.RUnlock()
 
-	if a.appConfig == nil {
-		return []config.S3Config{}, nil
+	if a.configManager == nil {
+		return []config.Snapshot{}, nil
 	}
 
-	return a.appConfig.S3DataSources, nil
+	return a.configManager.ListSnapshots()
 }
 
-// TestS3Connection 测试S3连接
-func (a *App) TestS3Connection(name, endpoint, accessKey, secretKey, region, bucket string) error {
-	s3Config := config.S3Config{
-		Name:      name,
-		Endpoint:  endpoint,
-		AccessKey: accessKey,
-		SecretKey: secretKey,
-		Region:    region,
-		Bucket:    bucket,
-	}
-
-	// 使用新的S3客户端
-	s3Client, err := s3.NewS3Client(s3Config)
+// RestoreConfigSnapshot 从快照恢复配置
+func (a *App) RestoreConfigSnapshot(id string) error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.configManager == nil || !a.configManager.IsEncryptionInitialized() {
+		return fmt.Errorf("请先设置主密码")
+	}
+
+	cfg, err := a.configManager.RestoreSnapshot(id)
 	if err != nil {
-		return fmt.Errorf("创建S3客户端失败: %v", err)
+		return fmt.Errorf("恢复快照失败: %v", err)
 	}
 
-	return s3Client.TestConnection(bucket)
+	a.appConfig = cfg
+
+	// 更新rclone配置
+	return a.rcloneManager.GenerateRcloneConfig(cfg.DataSources)
 }
 
-// ListFiles 列出文件
-func (a *App) ListFiles(s3Name, remotePath string) ([]rclone.FileInfo, error) {
+// IsPasswordSet 检查是否已设置主密码
+func (a *App) IsPasswordSet() bool {
 	a.configMutex.RLock()
 	defer a.configMutex.RUnlock()
 
-	// 查找S3配置
-	var targetConfig *config.S3Config
-	for _, config := range a.appConfig.S3DataSources {
-		if config.Name == s3Name {
-			targetConfig = &config
-			break
-		}
-	}
+	return a.configManager != nil && a.configManager.IsEncryptionInitialized()
+}
 
-	if targetConfig == nil {
-		return nil, fmt.Errorf("未找到S3配置: %s", s3Name)
-	}
+// AddDataSource 添加数据源，ID由后端生成
+func (a *App) AddDataSource(ds config.DataSource) error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
 
-	// 使用新的S3客户端
-	s3Client, err := s3.NewS3Client(*targetConfig)
-	if err != nil {
-		return nil, fmt.Errorf("创建S3客户端失败: %v", err)
+	if a.appConfig == nil {
+		return fmt.Errorf("配置未初始化，请先设置主密码")
 	}
 
-	s3Files, err := s3Client.ListFiles(targetConfig.Bucket, remotePath)
-	if err != nil {
-		return nil, err
+	ds.ID = config.NewDataSourceID()
+	if err := a.configManager.AddDataSource(a.appConfig, ds); err != nil {
+		return err
 	}
 
-	// 转换为rclone.FileInfo格式
-	var rcloneFiles []rclone.FileInfo
-	for _, file := range s3Files {
-		rcloneFiles = append(rcloneFiles, rclone.FileInfo{
-			Name:    file.Name,
-			Path:    file.Path,
-			Size:    file.Size,
-			ModTime: file.ModTime,
-			IsDir:   file.IsDir,
-		})
+	if err := a.configManager.SaveConfig(a.appConfig); err != nil {
+		return fmt.Errorf("保存配置失败: %v", err)
 	}
 
-	return rcloneFiles, nil
+	// 更新rclone配置
+	return a.rcloneManager.GenerateRcloneConfig(a.appConfig.DataSources)
 }
 
-// Mount 挂载S3到本地
-func (a *App) Mount(s3Name, remotePath string) error {
-	a.mountMutex.Lock()
-	defer a.mountMutex.Unlock()
+// AddS3DataSource 添加S3数据源
+func (a *App) AddS3DataSource(name, provider, endpoint, accessKey, secretKey, region, bucket, description string) error {
+	return a.AddDataSource(config.DataSource{
+		Name:        name,
+		Type:        config.TypeS3,
+		Description: description,
+		S3: &config.S3Config{
+			Provider:  provider,
+			Endpoint:  endpoint,
+			AccessKey: accessKey,
+			SecretKey: secretKey,
+			Region:    region,
+			Bucket:    bucket,
+
+			CredentialMode: config.CredentialStatic,
+		},
+	})
+}
 
-	// 生成挂载路径
-	homeDir, _ := os.UserHomeDir()
-	mountDir := filepath.Join(homeDir, "mounts", s3Name)
-	if remotePath != "" && remotePath != "/" {
-		mountDir = filepath.Join(mountDir, filepath.Base(remotePath))
+// UpdateDataSource 更新数据源，已挂载的数据源需要先卸载。密钥留空表示保持不变
+func (a *App) UpdateDataSource(ds config.DataSource) error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.appConfig == nil {
+		return fmt.Errorf("配置未初始化，请先设置主密码")
 	}
 
-	// 检查是否已经挂载
-	if _, exists := a.mountProcesses[s3Name]; exists {
-		return fmt.Errorf("数据源 '%s' 已经挂载", s3Name)
+	// 正在运行的rclone进程不会重新读取配置，修改连接参数前必须卸载
+	a.mountMutex.RLock()
+	mounted := len(a.sourceMounts(ds.ID)) > 0
+	a.mountMutex.RUnlock()
+	if mounted {
+		return fmt.Errorf("数据源 '%s' 正在挂载中，请先卸载后再修改", ds.Name)
 	}
 
-	// 执行挂载
-	process, err := a.rcloneManager.Mount(s3Name, remotePath, mountDir)
-	if err != nil {
+	if err := a.configManager.UpdateDataSource(a.appConfig, ds); err != nil {
 		return err
 	}
 
-	// 记录挂载信息
-	mountInfo := rclone.MountInfo{
-		Name:      s3Name,
-		Remote:    remotePath,
-		LocalPath: mountDir,
-		Status:    "mounted",
+	if err := a.configManager.SaveConfig(a.appConfig); err != nil {
+		return fmt.Errorf("保存配置失败: %v", err)
 	}
 
-	if process != nil && process.Process != nil {
-		// 注意：这里简化了PID获取，实际中可能需要更复杂的进程管理
-		mountInfo.PID = process.Process.Pid
-	}
+	// 更新rclone配置
+	return a.rcloneManager.GenerateRcloneConfig(a.appConfig.DataSources)
+}
 
-	a.mountProcesses[s3Name] = &mountInfo
-	return nil
+// UpdateS3DataSource 更新S3数据源，已挂载的数据源需要先卸载。secretKey 留空表示保持不变
+func (a *App) UpdateS3DataSource(id, name, provider, endpoint, accessKey, secretKey, region, bucket, description string) error {
+	return a.UpdateDataSource(config.DataSource{
+		ID:          id,
+		Name:        name,
+		Type:        config.TypeS3,
+		Description: description,
+		S3: &config.S3Config{
+			Provider:  provider,
+			Endpoint:  endpoint,
+			AccessKey: accessKey,
+			SecretKey: secretKey,
+			Region:    region,
+			Bucket:    bucket,
+
+			CredentialMode: config.CredentialStatic,
+		},
+	})
 }
 
-// Unmount 卸载S3
-func (a *App) Unmount(s3Name string) error {
-	a.mountMutex.Lock()
-	defer a.mountMutex.Unlock()
+// RemoveDataSource 删除数据源，会先卸载该数据源的挂载
+func (a *App) RemoveDataSource(id string) error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
 
-	mountInfo, exists := a.mountProcesses[s3Name]
-	if !exists {
-		return fmt.Errorf("数据源 '%s' 未挂载", s3Name)
+	if a.appConfig == nil {
+		return fmt.Errorf("配置未初始化，请先设置主密码")
 	}
 
-	// 卸载
-	if err := a.rcloneManager.Unmount(mountInfo.LocalPath); err != nil {
+	ds, err := a.configManager.FindDataSource(a.appConfig, id)
+	if err != nil {
 		return err
 	}
 
-	delete(a.mountProcesses, s3Name)
-	return nil
-}
+	a.mountMutex.Lock()
+	for _, mountInfo := range a.sourceMounts(id) {
+		supervisor := a.stopSupervisor(mountInfo.ID)
+		if err := a.rcloneManager.Unmount(mountInfo.ID, mountInfo.LocalPath); err != nil {
+			a.restoreSupervisor(mountInfo.ID, supervisor)
+			a.mountMutex.Unlock()
+			return fmt.Errorf("卸载数据源 '%s' 的挂载 %s 失败: %v", ds.Name, mountInfo.LocalPath, err)
+		}
+		delete(a.mountProcesses, mountInfo.ID)
+	}
 
-// GetMounts 获取挂载列表
-func (a *App) GetMounts() ([]rclone.MountInfo, error) {
-	a.mountMutex.RLock()
-	defer a.mountMutex.RUnlock()
+	// 同时删除该数据源保存的挂载定义，随后清理它们的日志
+	if err := a.configManager.RemoveDataSource(a.appConfig, id); err != nil {
+		a.mountMutex.Unlock()
+		return err
+	}
+	a.pruneMountLogs()
+	a.mountMutex.Unlock()
 
-	var mounts []rclone.MountInfo
-	for _, mount := range a.mountProcesses {
-		mounts = append(mounts, *mount)
+	if err := a.configManager.SaveConfig(a.appConfig); err != nil {
+		return fmt.Errorf("保存配置失败: %v", err)
 	}
 
-	return mounts, nil
+	// 更新rclone配置
+	return a.rcloneManager.GenerateRcloneConfig(a.appConfig.DataSources)
 }
 
-// SetGistConfig 设置Gist配置
-func (a *App) SetGistConfig(apiToken, gistID string) error {
+// RenameDataSource 重命名数据源，挂载按ID记录，不受影响
+func (a *App) RenameDataSource(id, newName string) error {
 	a.configMutex.Lock()
 	defer a.configMutex.Unlock()
 
 	if a.appConfig == nil {
-		return fmt.Errorf("配置未初始化")
+		return fmt.Errorf("配置未初始化，请先设置主密码")
 	}
 
-	// 检查加密是否已初始化
-	if !a.configManager.IsEncryptionInitialized() {
-		return fmt.Errorf("请先设置主密码后再保存配置")
+	if err := a.configManager.RenameDataSource(a.appConfig, id, newName); err != nil {
+		return err
 	}
 
-	a.appConfig.GistAPIToken = apiToken
-	a.appConfig.GistID = gistID
+	if err := a.configManager.SaveConfig(a.appConfig); err != nil {
+		return fmt.Errorf("保存配置失败: %v", err)
+	}
 
-	// 初始化Gist同步
-	if apiToken != "" {
-		a.gistSync = gistsync.NewGistSync(apiToken)
-		// 测试访问权限，但不阻止配置保存
-		if err := a.gistSync.TestGistAccess(); err != nil {
-			// 记录警告但不返回错误，允许用户保存配置
-			fmt.Printf("警告: Gist访问权限测试失败，但配置已保存: %v\n", err)
-		}
-	} else {
-		a.gistSync = nil
+	// 同步挂载记录中的显示名称
+	a.mountMutex.Lock()
+	for _, mountInfo := range a.sourceMounts(id) {
+		mountInfo.Name = newName
 	}
+	a.mountMutex.Unlock()
 
-	return a.configManager.SaveConfig(a.appConfig)
+	// 更新rclone配置
+	return a.rcloneManager.GenerateRcloneConfig(a.appConfig.DataSources)
 }
 
-// SyncToGist 同步配置到Gist
-func (a *App) SyncToGist() error {
+// GetDataSources 获取数据源列表，密钥只返回掩码，明文需通过 RevealSecret 获取
+func (a *App) GetDataSources() ([]config.MaskedDataSource, error) {
 	a.configMutex.RLock()
 	defer a.configMutex.RUnlock()
 
-	if a.appConfig == nil || a.gistSync == nil {
-		return fmt.Errorf("Gist配置未设置")
+	if a.appConfig == nil {
+		return []config.MaskedDataSource{}, nil
 	}
 
-	gistID, err := a.gistSync.UploadToGist(a.appConfig, a.appConfig.GistID)
+	sources := make([]config.MaskedDataSource, 0, len(a.appConfig.DataSources))
+	for _, ds := range a.appConfig.DataSources {
+		sources = append(sources, ds.Masked())
+	}
+
+	return sources, nil
+}
+
+// RevealSecret 再次校验主密码后返回数据源的明文密钥，键与 MaskedSecrets 一致
+func (a *App) RevealSecret(sourceID, password string) (map[string]string, error) {
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+
+	if a.appConfig == nil || !a.configManager.IsEncryptionInitialized() {
+		return nil, fmt.Errorf("请先设置主密码")
+	}
+
+	if err := a.configManager.VerifyPassword(password); err != nil {
+		if errors.Is(err, config.ErrIncorrectPassword) {
+			return nil, fmt.Errorf("主密码错误")
+		}
+		return nil, err
+	}
+
+	ds, err := a.configManager.FindDataSource(a.appConfig, sourceID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// 更新Gist ID
-	a.appConfig.GistID = gistID
-	return a.configManager.SaveConfig(a.appConfig)
+	a.ReportActivity()
+	return ds.Secrets(), nil
 }
 
-// SetAutoStart 设置开机自启动
-func (a *App) SetAutoStart(enabled bool) error {
-	a.configMutex.Lock()
-	defer a.configMutex.Unlock()
+// GetDefaultMountOptions 获取未单独配置的数据源使用的挂载参数
+func (a *App) GetDefaultMountOptions() config.MountOptions {
+	return config.DefaultMountOptions()
+}
 
-	if a.appConfig == nil {
-		return fmt.Errorf("配置未初始化")
+// GetS3Providers 获取内置的S3兼容服务商列表
+func (a *App) GetS3Providers() []config.Provider {
+	return config.Providers()
+}
+
+// TestDataSource 测试数据源连接，S3、WebDAV和SFTP直接使用Go客户端，其他类型交给rclone
+func (a *App) TestDataSource(ds config.DataSource) error {
+	// 先做本地校验，避免格式错误到客户端内部才报错
+	if err := config.ValidateDataSource(ds); err != nil {
+		return err
 	}
 
-	if enabled {
-		if err := a.autoStartManager.EnableAutoStart(); err != nil {
-			return err
+	switch ds.Type {
+	case config.TypeS3:
+		s3Client, err := s3.NewS3Client(*ds.S3)
+		if err != nil {
+			return fmt.Errorf("创建S3客户端失败: %v", err)
 		}
-	} else {
-		if err := a.autoStartManager.DisableAutoStart(); err != nil {
-			return err
+		return s3Client.TestConnection(ds.S3.Bucket)
+	case config.TypeWebDAV:
+		client, err := webdav.NewWebDAVClient(*ds.WebDAV)
+		if err != nil {
+			return fmt.Errorf("创建WebDAV客户端失败: %v", err)
+		}
+		return client.TestConnection()
+	case config.TypeSFTP:
+		client, err := sftp.NewSFTPClient(*ds.SFTP)
+		if err != nil {
+			return fmt.Errorf("创建SFTP客户端失败: %v", err)
 		}
+		defer client.Close()
+		return client.TestConnection()
+	default:
+		return a.rcloneManager.TestConnection(ds)
 	}
+}
 
-	a.appConfig.AutoStart = enabled
-	return a.configManager.SaveConfig(a.appConfig)
+// TestS3Connection 测试S3连接
+func (a *App) TestS3Connection(name, provider, endpoint, accessKey, secretKey, region, bucket string) error {
+	return a.TestDataSource(config.DataSource{
+		Name: name,
+		Type: config.TypeS3,
+		S3: &config.S3Config{
+			Provider:  provider,
+			Endpoint:  endpoint,
+			AccessKey: accessKey,
+			SecretKey: secretKey,
+			Region:    region,
+			Bucket:    bucket,
+
+			CredentialMode: config.CredentialStatic,
+		},
+	})
 }
 
-// GetGistConfig 获取Gist配置
-func (a *App) GetGistConfig() (apiToken, gistID string, hasToken bool, err error) {
+// ListFiles 列出文件，按数据源类型选择对应的客户端
+func (a *App) ListFiles(sourceID, remotePath string) ([]rclone.FileInfo, error) {
 	a.configMutex.RLock()
 	defer a.configMutex.RUnlock()
 
 	if a.appConfig == nil {
-		return "", "", false, fmt.Errorf("配置未初始化")
+		return nil, fmt.Errorf("配置未初始化，请先设置主密码")
 	}
 
-	// 只返回token是否存在和脱敏信息，不返回实际token
+	ds, err := a.configManager.FindDataSource(a.appConfig, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case ds.Type == config.TypeS3 && ds.S3 != nil:
+		s3Client, err := s3.NewS3Client(*ds.S3)
+		if err != nil {
+			return nil, fmt.Errorf("创建S3客户端失败: %v", err)
+		}
+
+		files, err := s3Client.ListFiles(ds.S3.Bucket, remotePath)
+		if err != nil {
+			return nil, err
+		}
+		return toRcloneFiles(files), nil
+	case ds.Type == config.TypeWebDAV && ds.WebDAV != nil:
+		client, err := webdav.NewWebDAVClient(*ds.WebDAV)
+		if err != nil {
+			return nil, fmt.Errorf("创建WebDAV客户端失败: %v", err)
+		}
+
+		files, err := client.ListFiles(remotePath)
+		if err != nil {
+			return nil, err
+		}
+		return toRcloneFiles(files), nil
+	case ds.Type == config.TypeSFTP && ds.SFTP != nil:
+		client, err := sftp.NewSFTPClient(*ds.SFTP)
+		if err != nil {
+			return nil, fmt.Errorf("创建SFTP客户端失败: %v", err)
+		}
+		defer client.Close()
+
+		files, err := client.ListFiles(remotePath)
+		if err != nil {
+			return nil, err
+		}
+		return toRcloneFiles(files), nil
+	default:
+		// 任意rclone后端没有对应的Go客户端，使用rclone lsjson列出
+		return a.rcloneManager.ListFiles(rclone.RemoteName(*ds), rclone.RemotePath(*ds, remotePath))
+	}
+}
+
+// toRcloneFiles 将各客户端的文件列表转换为rclone.FileInfo格式，各客户端的FileInfo字段与其一致
+func toRcloneFiles[T s3.FileInfo | webdav.FileInfo | sftp.FileInfo](files []T) []rclone.FileInfo {
+	rcloneFiles := make([]rclone.FileInfo, 0, len(files))
+	for _, file := range files {
+		rcloneFiles = append(rcloneFiles, rclone.FileInfo(file))
+	}
+	return rcloneFiles
+}
+
+// Mount 挂载数据源到挂载根目录下，返回新的挂载记录
+func (a *App) Mount(sourceID, remotePath string) (*rclone.MountInfo, error) {
+	return a.MountAt(sourceID, remotePath, "")
+}
+
+// MountAt 挂载数据源到指定路径，localPath 为空时挂载到挂载根目录下。
+// 同一数据源可以同时挂载多个不同的远程路径。
+func (a *App) MountAt(sourceID, remotePath, localPath string) (*rclone.MountInfo, error) {
+	return a.mount(uuid.NewString(), sourceID, remotePath, localPath)
+}
+
+// mount 使用指定的挂载ID执行挂载，自动挂载时ID与保存的挂载定义一致
+func (a *App) mount(mountID, sourceID, remotePath, localPath string) (*rclone.MountInfo, error) {
+	a.configMutex.RLock()
+	if a.appConfig == nil {
+		a.configMutex.RUnlock()
+		return nil, fmt.Errorf("配置未初始化，请先设置主密码")
+	}
+
+	found, err := a.configManager.FindDataSource(a.appConfig, sourceID)
+	if err != nil {
+		a.configMutex.RUnlock()
+		return nil, err
+	}
+	ds := *found
+	mountRoot := a.mountRoot()
+	logLevel := a.appConfig.RcloneLogLevel
+	a.configMutex.RUnlock()
+
+	// 生成挂载路径
+	explicit := localPath != ""
+	mountDir := config.ExpandPath(localPath)
+	if !explicit {
+		if mountDir, err = defaultMountPath(mountRoot, ds.Name, remotePath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.ValidateMountPath("localPath", mountDir); err != nil {
+		return nil, err
+	}
+
+	remotePath = "/" + strings.Trim(remotePath, "/")
+	mountInfo := rclone.MountInfo{
+		ID:        mountID,
+		SourceID:  sourceID,
+		Name:      ds.Name,
+		Remote:    remotePath,
+		LocalPath: mountDir,
+
+		ExplicitPath: explicit,
+	}
+
+	// 检查是否已经挂载，并预留挂载ID和路径。rclone挂载和等待就绪可能需要数十秒，
+	// 期间不持有 configMutex 和 mountMutex，其他挂载任务和只读调用不会被阻塞
+	a.mountMutex.Lock()
+	if err := a.checkMountConflict(mountInfo); err != nil {
+		a.mountMutex.Unlock()
+		return nil, err
+	}
+	a.pendingMounts[mountID] = &mountInfo
+	epoch := a.mountEpoch
+	a.mountMutex.Unlock()
+
+	// 执行挂载，日志级别在rc服务启动前设置
+	if err := a.rcloneManager.SetLogLevel(logLevel); err != nil {
+		fmt.Printf("设置rclone日志级别失败: %v\n", err)
+	}
+	remoteName, rclonePath, opts := rclone.RemoteName(ds), rclone.RemotePath(ds, remotePath), ds.EffectiveMountOptions()
+	err = a.rcloneManager.Mount(mountID, remoteName, rclonePath, mountDir, opts)
+
+	// 记录挂载信息
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+	a.mountMutex.Lock()
+	defer a.mountMutex.Unlock()
+
+	delete(a.pendingMounts, mountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 挂载期间配置被锁定并卸载了所有挂载，或数据源已被删除
+	cancelled := a.mountEpoch != epoch
+	if !cancelled && a.appConfig != nil {
+		_, findErr := a.configManager.FindDataSource(a.appConfig, sourceID)
+		cancelled = findErr != nil
+	}
+	if cancelled {
+		if err := a.rcloneManager.Unmount(mountID, mountDir); err != nil {
+			return nil, fmt.Errorf("挂载已取消，但卸载失败: %v", err)
+		}
+		return nil, mountConflictError("挂载期间配置已锁定或数据源已删除，挂载已取消")
+	}
+
+	mountInfo.PID = a.rcloneManager.DaemonPID()
+	mountInfo.Status = rclone.MountStatusMounted
+	mountInfo.AutoMount = a.isAutoMount(mountID)
+
+	a.mountProcesses[mountInfo.ID] = &mountInfo
+	a.startSupervisor(mountInfo.ID, remoteName, rclonePath, opts)
+	result := mountInfo
+	return &result, nil
+}
+
+// checkMountConflict 检查挂载ID、远程路径和本地路径是否已被已有或正在进行的挂载占用，
+// 调用方需持有 mountMutex
+func (a *App) checkMountConflict(m rclone.MountInfo) error {
+	if _, exists := a.mountProcesses[m.ID]; exists {
+		return mountConflictError(fmt.Sprintf("挂载 '%s' 已存在", m.ID))
+	}
+	if _, exists := a.pendingMounts[m.ID]; exists {
+		return mountConflictError(fmt.Sprintf("挂载 '%s' 正在进行", m.ID))
+	}
+
+	for _, mounts := range []map[string]*rclone.MountInfo{a.mountProcesses, a.pendingMounts} {
+		for _, mountInfo := range mounts {
+			if mountInfo.SourceID == m.SourceID && mountInfo.Remote == m.Remote {
+				return mountConflictError(fmt.Sprintf("数据源 '%s' 的 %s 已经挂载在 %s", m.Name, m.Remote, mountInfo.LocalPath))
+			}
+			if mountInfo.LocalPath == m.LocalPath {
+				return mountConflictError(fmt.Sprintf("路径 %s 已被数据源 '%s' 使用", m.LocalPath, mountInfo.Name))
+			}
+		}
+	}
+	return nil
+}
+
+// Unmount 按挂载ID卸载
+func (a *App) Unmount(mountID string) error {
+	return a.unmount(mountID, false)
+}
+
+// unmount 按挂载ID卸载，keepLog 为true时保留挂载日志，用于随后以同一ID重新挂载
+func (a *App) unmount(mountID string, keepLog bool) error {
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+	a.mountMutex.Lock()
+	defer a.mountMutex.Unlock()
+
+	mountInfo, exists := a.mountProcesses[mountID]
+	if !exists {
+		return fmt.Errorf("挂载 '%s' 不存在", mountID)
+	}
+
+	// 先停止监督，避免卸载后被重新挂载
+	supervisor := a.stopSupervisor(mountID)
+	if err := a.rcloneManager.Unmount(mountInfo.ID, mountInfo.LocalPath); err != nil {
+		a.restoreSupervisor(mountID, supervisor)
+		return err
+	}
+
+	if keepLog {
+		delete(a.mountProcesses, mountID)
+		return nil
+	}
+	a.removeMountRecord(mountID)
+	return nil
+}
+
+// removeMountRecord 删除挂载记录，挂载未保存为挂载定义时同时删除其日志。
+// 调用方需持有 configMutex 和 mountMutex
+func (a *App) removeMountRecord(mountID string) {
+	delete(a.mountProcesses, mountID)
+	if a.isSavedMount(mountID) {
+		return
+	}
+	if err := a.rcloneManager.RemoveMountLog(mountID); err != nil {
+		fmt.Printf("%v\n", err)
+	}
+}
+
+// pruneMountLogs 删除既没有挂载记录也没有保存挂载定义的日志，例如已删除的数据源的挂载日志。
+// 调用方需持有 configMutex 和 mountMutex
+func (a *App) pruneMountLogs() {
+	if a.appConfig == nil {
+		return
+	}
+	for _, mountID := range a.rcloneManager.MountLogIDs() {
+		if _, exists := a.mountProcesses[mountID]; exists {
+			continue
+		}
+		if _, exists := a.pendingMounts[mountID]; exists {
+			continue
+		}
+		if a.isSavedMount(mountID) {
+			continue
+		}
+		if err := a.rcloneManager.RemoveMountLog(mountID); err != nil {
+			fmt.Printf("%v\n", err)
+		}
+	}
+}
+
+// isSavedMount 检查挂载ID是否为保存的挂载定义，调用方需持有 configMutex
+func (a *App) isSavedMount(mountID string) bool {
+	if a.appConfig == nil {
+		return false
+	}
+	for _, entry := range a.appConfig.Mounts {
+		if entry.ID == mountID {
+			return true
+		}
+	}
+	return false
+}
+
+// isAutoMount 检查挂载是否保存为自动挂载，调用方需持有 configMutex
+func (a *App) isAutoMount(mountID string) bool {
+	if a.appConfig == nil {
+		return false
+	}
+	for _, entry := range a.appConfig.Mounts {
+		if entry.ID == mountID {
+			return entry.AutoMount
+		}
+	}
+	return false
+}
+
+// GetMountEntries 获取保存的挂载定义
+func (a *App) GetMountEntries() ([]config.MountEntry, error) {
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+
+	if a.appConfig == nil {
+		return []config.MountEntry{}, nil
+	}
+
+	return append([]config.MountEntry{}, a.appConfig.Mounts...), nil
+}
+
+// SetMountAtStartup 设置挂载是否在解锁后自动挂载。
+// mountID 可以是当前的挂载记录，也可以是未挂载的已保存定义；关闭时删除保存的定义。
+func (a *App) SetMountAtStartup(mountID string, enabled bool) error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.appConfig == nil || !a.configManager.IsEncryptionInitialized() {
+		return fmt.Errorf("请先设置主密码")
+	}
+
+	a.mountMutex.Lock()
+	defer a.mountMutex.Unlock()
+
+	mountInfo, active := a.mountProcesses[mountID]
+
+	if !enabled {
+		if err := a.configManager.RemoveMountEntry(a.appConfig, mountID); err != nil {
+			return err
+		}
+		// 未挂载的定义删除后不会再使用其日志
+		if !active {
+			if err := a.rcloneManager.RemoveMountLog(mountID); err != nil {
+				fmt.Printf("%v\n", err)
+			}
+		}
+	} else {
+		var entry *config.MountEntry
+		for i := range a.appConfig.Mounts {
+			if a.appConfig.Mounts[i].ID == mountID {
+				entry = &a.appConfig.Mounts[i]
+				break
+			}
+		}
+
+		switch {
+		case entry != nil:
+			updated := *entry
+			updated.AutoMount = true
+			if err := a.configManager.SaveMountEntry(a.appConfig, updated); err != nil {
+				return err
+			}
+		case active:
+			newEntry := config.MountEntry{
+				ID:         mountInfo.ID,
+				SourceID:   mountInfo.SourceID,
+				RemotePath: mountInfo.Remote,
+				AutoMount:  true,
+			}
+			if mountInfo.ExplicitPath {
+				newEntry.LocalPath = mountInfo.LocalPath
+			}
+			if err := a.configManager.SaveMountEntry(a.appConfig, newEntry); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("挂载 '%s' 不存在", mountID)
+		}
+	}
+
+	if err := a.configManager.SaveConfig(a.appConfig); err != nil {
+		return fmt.Errorf("保存配置失败: %v", err)
+	}
+
+	if active {
+		mountInfo.AutoMount = enabled
+	}
+	return nil
+}
+
+// startAutoMount 在后台启动自动挂载，只执行一次
+func (a *App) startAutoMount() {
+	a.autoMountOnce.Do(func() {
+		go a.autoMount()
+	})
+}
+
+// autoMount 按保存顺序启动自动挂载任务，同时运行的任务数不超过 autoMountConcurrency
+func (a *App) autoMount() {
+	// 先接管已存在的挂载，避免重复挂载到同一挂载点
+	if err := a.refreshMounts(); err != nil {
+		fmt.Printf("读取挂载表失败: %v\n", err)
+	}
+
+	a.configMutex.RLock()
+	var entries []config.MountEntry
+	if a.appConfig != nil {
+		for _, entry := range a.appConfig.Mounts {
+			if entry.AutoMount {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	a.configMutex.RUnlock()
+
+	sem := make(chan struct{}, autoMountConcurrency)
+	var wg sync.WaitGroup
+
+	for _, entry := range entries {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(entry config.MountEntry) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			a.runMountJob(entry)
+		}(entry)
+	}
+
+	wg.Wait()
+}
+
+// runMountJob 执行单个自动挂载任务，失败时按指数退避重试，例如等待网络就绪
+// 返回任务的最终状态
+func (a *App) runMountJob(entry config.MountEntry) string {
+	// 上次运行留下、已由 refreshMounts 接管的挂载沿用保存的ID，无需再次挂载
+	a.mountMutex.RLock()
+	_, adopted := a.mountProcesses[entry.ID]
+	a.mountMutex.RUnlock()
+	if adopted {
+		a.emitMountJob(entry, mountJobMounted, 1, nil)
+		return mountJobMounted
+	}
+
+	delay := autoMountInitialDelay
+
+	for attempt := 1; ; attempt++ {
+		a.emitMountJob(entry, mountJobMounting, attempt, nil)
+
+		_, err := a.mount(entry.ID, entry.SourceID, entry.RemotePath, entry.LocalPath)
+		if err == nil {
+			a.emitMountJob(entry, mountJobMounted, attempt, nil)
+			return mountJobMounted
+		}
+
+		if attempt >= autoMountMaxAttempts || !isRetryableMountError(err) || !a.IsPasswordSet() {
+			a.emitMountJob(entry, mountJobFailed, attempt, err)
+			return mountJobFailed
+		}
+
+		a.emitMountJob(entry, mountJobRetrying, attempt, err)
+		time.Sleep(delay)
+
+		delay *= 2
+		if delay > autoMountMaxRetryDelay {
+			delay = autoMountMaxRetryDelay
+		}
+	}
+}
+
+// isRetryableMountError 配置错误和挂载冲突重试也不会成功
+func isRetryableMountError(err error) bool {
+	var verr *config.ValidationError
+	var conflict mountConflictError
+	return !errors.As(err, &verr) && !errors.As(err, &conflict)
+}
+
+// emitMountJob 发送自动挂载任务状态
+func (a *App) emitMountJob(entry config.MountEntry, status string, attempt int, err error) {
+	if a.ctx == nil {
+		return
+	}
+
+	event := MountJobEvent{
+		MountID:  entry.ID,
+		SourceID: entry.SourceID,
+		Status:   status,
+		Attempt:  attempt,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	runtime.EventsEmit(a.ctx, EventMountJob, event)
+}
+
+// sourceMounts 返回数据源的所有挂载，调用方需持有 mountMutex
+func (a *App) sourceMounts(sourceID string) []*rclone.MountInfo {
+	var mounts []*rclone.MountInfo
+	for _, mountInfo := range a.mountProcesses {
+		if mountInfo.SourceID == sourceID {
+			mounts = append(mounts, mountInfo)
+		}
+	}
+	return mounts
+}
+
+// defaultMountPath 生成挂载根目录下的默认挂载路径。
+// 子路径挂载放在数据源目录旁边而不是里面，避免嵌套在同一数据源的根挂载中，
+// 例如 photos/2024 挂载到 <根目录>/<名称>_photos_2024。
+// 生成的路径必须位于根目录之下，旧版本允许的 . 或 .. 等名称会被拒绝。
+func defaultMountPath(root, name, remotePath string) (string, error) {
+	component := name
+	if remotePath = strings.Trim(remotePath, "/"); remotePath != "" {
+		component = name + "_" + strings.ReplaceAll(remotePath, "/", "_")
+	}
+
+	invalid := func() error {
+		return &config.ValidationError{Fields: []config.FieldError{{
+			Field:   "localPath",
+			Code:    config.CodeInvalidValue,
+			Message: fmt.Sprintf("数据源名称 '%s' 不能用作挂载目录名，请重命名数据源或指定挂载路径", name),
+		}}}
+	}
+
+	// 只由 . 组成的名称会被 Join 解析为根目录本身或其上级目录
+	if strings.Trim(component, ".") == "" || strings.ContainsRune(component, filepath.Separator) {
+		return "", invalid()
+	}
+
+	path := filepath.Join(root, component)
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", invalid()
+	}
+	return path, nil
+}
+
+// mountRoot 返回挂载根目录，调用方需持有 configMutex
+func (a *App) mountRoot() string {
+	if a.appConfig == nil || a.appConfig.MountDirectory == "" {
+		return config.DefaultMountDirectory()
+	}
+	return a.appConfig.MountDirectory
+}
+
+// GetMountDirectory 获取挂载根目录
+func (a *App) GetMountDirectory() string {
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+
+	return a.mountRoot()
+}
+
+// SetMountDirectory 设置挂载根目录，返回仍挂载在旧根目录下的挂载，
+// 前端可据此询问用户是否调用 RemountToMountDirectory 移动到新位置
+func (a *App) SetMountDirectory(dir string) ([]rclone.MountInfo, error) {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.appConfig == nil || !a.configManager.IsEncryptionInitialized() {
+		return nil, fmt.Errorf("请先设置主密码")
+	}
+
+	dir = config.ExpandPath(dir)
+	if err := config.ValidateMountPath("mountDirectory", dir); err != nil {
+		return nil, err
+	}
+
+	oldRoot := a.mountRoot()
+	a.appConfig.MountDirectory = dir
+	if err := a.configManager.SaveConfig(a.appConfig); err != nil {
+		return nil, fmt.Errorf("保存配置失败: %v", err)
+	}
+
+	moved := []rclone.MountInfo{}
+	if oldRoot == dir {
+		return moved, nil
+	}
+
+	a.mountMutex.RLock()
+	defer a.mountMutex.RUnlock()
+
+	for _, mountInfo := range a.mountProcesses {
+		if !mountInfo.ExplicitPath {
+			moved = append(moved, *mountInfo)
+		}
+	}
+
+	return moved, nil
+}
+
+// RemountToMountDirectory 将指定的挂载卸载后重新挂载到当前挂载根目录下
+func (a *App) RemountToMountDirectory(mountIDs []string) error {
+	var errs []error
+
+	for _, mountID := range mountIDs {
+		a.mountMutex.RLock()
+		mountInfo, exists := a.mountProcesses[mountID]
+		var current rclone.MountInfo
+		if exists {
+			current = *mountInfo
+		}
+		a.mountMutex.RUnlock()
+
+		if !exists || current.ExplicitPath {
+			continue
+		}
+
+		if err := a.unmount(mountID, true); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// 沿用原挂载ID，保存的挂载定义和日志依然对应
+		if _, err := a.mount(mountID, current.SourceID, current.Remote, ""); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetMounts 获取挂载列表
+func (a *App) GetMounts() ([]rclone.MountInfo, error) {
+	a.mountMutex.RLock()
+	defer a.mountMutex.RUnlock()
+
+	var mounts []rclone.MountInfo
+	for _, mount := range a.mountProcesses {
+		mounts = append(mounts, *mount)
+	}
+
+	return mounts, nil
+}
+
+// GetTransferStats 获取rc服务的传输统计，所有挂载共用一个rc服务
+func (a *App) GetTransferStats() (*rclone.RCStats, error) {
+	return a.rcloneManager.Stats()
+}
+
+// GetMountLog 获取挂载日志的最后 lines 行，lines 不大于0时返回最近200行
+func (a *App) GetMountLog(mountID string, lines int) ([]string, error) {
+	if lines <= 0 {
+		lines = defaultMountLogLines
+	}
+	if lines > maxMountLogLines {
+		lines = maxMountLogLines
+	}
+	return a.rcloneManager.MountLog(mountID, lines)
+}
+
+// WatchMountLog 开始实时查看挂载日志，新增的行通过 EventMountLog 事件发送
+func (a *App) WatchMountLog(mountID string) error {
+	if err := rclone.CheckMountID(mountID); err != nil {
+		return err
+	}
+
+	a.logMutex.Lock()
+	defer a.logMutex.Unlock()
+
+	if _, exists := a.logWatchers[mountID]; exists {
+		return nil
+	}
+	stop := make(chan struct{})
+	a.logWatchers[mountID] = stop
+	go a.tailMountLog(mountID, stop)
+	return nil
+}
+
+// UnwatchMountLog 停止实时查看挂载日志
+func (a *App) UnwatchMountLog(mountID string) {
+	a.logMutex.Lock()
+	defer a.logMutex.Unlock()
+
+	if stop, exists := a.logWatchers[mountID]; exists {
+		close(stop)
+		delete(a.logWatchers, mountID)
+	}
+}
+
+// tailMountLog 定期读取日志新增的完整行。日志轮转后文件变小，从头开始读取
+func (a *App) tailMountLog(mountID string, stop chan struct{}) {
+	path := a.rcloneManager.LogPath(mountID)
+
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	ticker := time.NewTicker(mountLogPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.Size() < offset {
+			offset = 0
+		}
+		if info.Size() == offset {
+			continue
+		}
+
+		data, err := readFileRange(path, offset, info.Size())
+		if err != nil {
+			continue
+		}
+
+		// 只发送完整的行，未写完的行留到下次读取
+		end := bytes.LastIndexByte(data, '\n')
+		if end < 0 {
+			continue
+		}
+		offset += int64(end + 1)
+
+		if a.ctx != nil {
+			lines := strings.Split(string(data[:end]), "\n")
+			runtime.EventsEmit(a.ctx, EventMountLog, MountLogEvent{MountID: mountID, Lines: lines})
+		}
+	}
+}
+
+// readFileRange 读取文件 [start, end) 范围的内容
+func readFileRange(path string, start, end int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data := make([]byte, end-start)
+	n, err := f.ReadAt(data, start)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+// SetGistConfig 设置Gist配置
+func (a *App) SetGistConfig(apiToken, gistID string) error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.appConfig == nil {
+		return fmt.Errorf("配置未初始化")
+	}
+
+	// 检查加密是否已初始化
+	if !a.configManager.IsEncryptionInitialized() {
+		return fmt.Errorf("请先设置主密码后再保存配置")
+	}
+
+	a.appConfig.GistAPIToken = apiToken
+	a.appConfig.GistID = gistID
+
+	// 初始化Gist同步
+	if apiToken != "" {
+		a.gistSync = gistsync.NewGistSync(apiToken)
+		// 测试访问权限，但不阻止配置保存
+		if err := a.gistSync.TestGistAccess(); err != nil {
+			// 记录警告但不返回错误，允许用户保存配置
+			fmt.Printf("警告: Gist访问权限测试失败，但配置已保存: %v\n", err)
+		}
+	} else {
+		a.gistSync = nil
+	}
+
+	return a.configManager.SaveConfig(a.appConfig)
+}
+
+// SyncToGist 同步配置到Gist
+func (a *App) SyncToGist() error {
+	// 上传后需要写入 GistID，使用写锁
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.appConfig == nil || a.gistSync == nil {
+		return fmt.Errorf("Gist配置未设置")
+	}
+
+	gistID, err := a.gistSync.UploadToGist(a.appConfig, a.appConfig.GistID)
+	if err != nil {
+		return err
+	}
+
+	// 更新Gist ID
+	a.appConfig.GistID = gistID
+	return a.configManager.SaveConfig(a.appConfig)
+}
+
+// ExportConfig 将完整配置加密导出到文件
+func (a *App) ExportConfig(path, passphrase string) error {
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+
+	if a.appConfig == nil || !a.configManager.IsEncryptionInitialized() {
+		return fmt.Errorf("请先设置主密码")
+	}
+
+	return config.ExportToFile(path, a.appConfig, passphrase)
+}
+
+// ImportConfig 从导出文件导入配置，mode 为 replace 或 merge
+func (a *App) ImportConfig(path, passphrase, mode string) (*config.ImportResult, error) {
+	switch config.ImportMode(mode) {
+	case config.ImportModeReplace, config.ImportModeMerge:
+	default:
+		return nil, fmt.Errorf("不支持的导入方式: %s", mode)
+	}
+
+	if !a.IsPasswordSet() {
+		return nil, fmt.Errorf("请先设置主密码")
+	}
+
+	// 派生导出口令的密钥需要较多内存和时间，在获取配置锁之前完成
+	imported, err := config.ImportFromFile(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.appConfig == nil || !a.configManager.IsEncryptionInitialized() {
+		return nil, fmt.Errorf("请先设置主密码")
+	}
+
+	var result *config.ImportResult
+	newConfig := *a.appConfig
+	newConfig.DataSources = append([]config.DataSource{}, a.appConfig.DataSources...)
+	newConfig.Mounts = append([]config.MountEntry{}, a.appConfig.Mounts...)
+
+	switch config.ImportMode(mode) {
+	case config.ImportModeReplace:
+		// 替换后不再存在的数据源的挂载需要先卸载，与删除数据源相同
+		if err := a.unmountRemovedSources(imported); err != nil {
+			return nil, err
+		}
+		newConfig = *imported
+		result = &config.ImportResult{
+			Mode:       config.ImportModeReplace,
+			Added:      []string{},
+			Collisions: []config.DataSourceCollision{},
+		}
+		for _, ds := range imported.DataSources {
+			result.Added = append(result.Added, ds.Name)
+		}
+	case config.ImportModeMerge:
+		result = config.MergeConfig(&newConfig, imported)
+	}
+
+	if err := a.configManager.SaveConfig(&newConfig); err != nil {
+		return nil, fmt.Errorf("保存配置失败: %v", err)
+	}
+	a.appConfig = &newConfig
+
+	a.mountMutex.Lock()
+	a.pruneMountLogs()
+	a.mountMutex.Unlock()
+
+	// 更新rclone配置
+	if err := a.rcloneManager.GenerateRcloneConfig(a.appConfig.DataSources); err != nil {
+		return nil, fmt.Errorf("生成rclone配置失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// unmountRemovedSources 卸载数据源不在新配置中的挂载，任一卸载失败时中止。
+// 调用方需持有 configMutex
+func (a *App) unmountRemovedSources(newConfig *config.AppConfig) error {
+	sources := make(map[string]bool, len(newConfig.DataSources))
+	for _, ds := range newConfig.DataSources {
+		sources[ds.ID] = true
+	}
+
+	a.mountMutex.Lock()
+	defer a.mountMutex.Unlock()
+
+	for mountID, mountInfo := range a.mountProcesses {
+		if sources[mountInfo.SourceID] {
+			continue
+		}
+		supervisor := a.stopSupervisor(mountID)
+		if err := a.rcloneManager.Unmount(mountID, mountInfo.LocalPath); err != nil {
+			a.restoreSupervisor(mountID, supervisor)
+			return fmt.Errorf("卸载挂载 %s 失败，已取消导入: %v", mountInfo.LocalPath, err)
+		}
+		delete(a.mountProcesses, mountID)
+	}
+	return nil
+}
+
+// Lock 立即锁定配置，需要重新输入主密码才能继续操作
+func (a *App) Lock() error {
+	return a.lock(lockReasonManual)
+}
+
+// ReportActivity 记录用户操作，前端在用户输入时调用以推迟自动锁定
+func (a *App) ReportActivity() {
+	a.lastActivity.Store(time.Now().UnixNano())
+}
+
+// SetAutoLock 设置空闲自动锁定时间（分钟，0 表示关闭）和锁定时的挂载处理方式
+func (a *App) SetAutoLock(minutes int, mountPolicy string) error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.appConfig == nil || !a.configManager.IsEncryptionInitialized() {
+		return fmt.Errorf("请先设置主密码")
+	}
+
+	if minutes < 0 || minutes > config.MaxAutoLockMinutes {
+		return fmt.Errorf("自动锁定时间必须在0到%d分钟之间", config.MaxAutoLockMinutes)
+	}
+
+	if mountPolicy != config.LockKeepMounts && mountPolicy != config.LockUnmountAll {
+		return fmt.Errorf("不支持的挂载处理方式: %s", mountPolicy)
+	}
+
+	a.appConfig.AutoLockMinutes = minutes
+	a.appConfig.LockMountPolicy = mountPolicy
+	return a.configManager.SaveConfig(a.appConfig)
+}
+
+// GetAutoLock 获取空闲自动锁定设置
+func (a *App) GetAutoLock() (minutes int, mountPolicy string, err error) {
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+
+	if a.appConfig == nil {
+		return 0, "", fmt.Errorf("配置未初始化")
+	}
+
+	mountPolicy = a.appConfig.LockMountPolicy
+	if mountPolicy == "" {
+		mountPolicy = config.LockKeepMounts
+	}
+	return a.appConfig.AutoLockMinutes, mountPolicy, nil
+}
+
+// SetRcloneLogLevel 设置rclone日志级别，rc服务已运行时立即生效
+func (a *App) SetRcloneLogLevel(level string) error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.appConfig == nil || !a.configManager.IsEncryptionInitialized() {
+		return fmt.Errorf("请先设置主密码")
+	}
+
+	if !config.ValidLogLevel(level) {
+		return fmt.Errorf("不支持的日志级别: %s", level)
+	}
+
+	a.appConfig.RcloneLogLevel = level
+	if err := a.configManager.SaveConfig(a.appConfig); err != nil {
+		return fmt.Errorf("保存配置失败: %v", err)
+	}
+
+	if err := a.rcloneManager.SetLogLevel(level); err != nil {
+		return fmt.Errorf("设置rclone日志级别失败: %v", err)
+	}
+	return nil
+}
+
+// GetRcloneLogLevel 获取rclone日志级别
+func (a *App) GetRcloneLogLevel() (string, error) {
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+
+	if a.appConfig == nil {
+		return "", fmt.Errorf("配置未初始化")
+	}
+
+	if a.appConfig.RcloneLogLevel == "" {
+		return config.LogLevelNotice, nil
+	}
+	return a.appConfig.RcloneLogLevel, nil
+}
+
+// lock 清除内存中的配置和密钥，并按设置处理挂载
+func (a *App) lock(reason string) error {
+	a.configMutex.Lock()
+
+	if a.configManager == nil || !a.configManager.IsEncryptionInitialized() {
+		a.configMutex.Unlock()
+		return nil
+	}
+
+	var errs []error
+	if a.appConfig != nil && a.appConfig.LockMountPolicy == config.LockUnmountAll {
+		a.mountMutex.Lock()
+		a.mountEpoch++
+		for id, mountInfo := range a.mountProcesses {
+			supervisor := a.stopSupervisor(id)
+			if err := a.rcloneManager.Unmount(mountInfo.ID, mountInfo.LocalPath); err != nil {
+				a.restoreSupervisor(id, supervisor)
+				errs = append(errs, fmt.Errorf("卸载数据源 '%s' 失败: %v", mountInfo.Name, err))
+				continue
+			}
+			a.removeMountRecord(id)
+		}
+		a.mountMutex.Unlock()
+
+		// rclone.conf 中同样包含明文密钥
+		if err := a.rcloneManager.RemoveConfig(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if a.appConfig != nil {
+		a.appConfig.WipeSecrets()
+	}
+	a.appConfig = nil
+	a.gistSync = nil
+
+	a.configManager.Close()
+	a.configManager, _ = config.NewConfigManager(a.configDir, "")
+
+	a.configMutex.Unlock()
+
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, EventConfigLocked, reason)
+	}
+
+	return errors.Join(errs...)
+}
+
+// monitorIdle 空闲时间超过设置值时自动锁定
+func (a *App) monitorIdle() {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		a.configMutex.RLock()
+		minutes := 0
+		if a.appConfig != nil && a.configManager != nil && a.configManager.IsEncryptionInitialized() {
+			minutes = a.appConfig.AutoLockMinutes
+		}
+		a.configMutex.RUnlock()
+
+		if minutes <= 0 {
+			continue
+		}
+
+		idle := time.Since(time.Unix(0, a.lastActivity.Load()))
+		if idle < time.Duration(minutes)*time.Minute {
+			continue
+		}
+
+		if err := a.lock(lockReasonIdle); err != nil {
+			fmt.Printf("自动锁定时处理挂载失败: %v\n", err)
+		}
+	}
+}
+
+// monitorConfigFile 配置文件被其他实例或Gist恢复修改后重新加载并重新生成rclone配置
+func (a *App) monitorConfigFile() {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		reloaded, err := a.reloadConfigIfChanged()
+		if errors.Is(err, config.ErrKeyChanged) || errors.Is(err, config.ErrIncorrectPassword) {
+			if err := a.lock(lockReasonExternal); err != nil {
+				fmt.Printf("锁定配置失败: %v\n", err)
+			}
+			continue
+		}
+		if err != nil {
+			fmt.Printf("重新加载配置失败: %v\n", err)
+			continue
+		}
+
+		if reloaded && a.ctx != nil {
+			runtime.EventsEmit(a.ctx, EventConfigReloaded)
+		}
+	}
+}
+
+// reloadConfigIfChanged 配置文件有外部修改时重新加载，返回是否重新加载
+func (a *App) reloadConfigIfChanged() (bool, error) {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.configManager == nil || !a.configManager.IsEncryptionInitialized() {
+		return false, nil
+	}
+
+	if !a.configManager.ChangedOnDisk() {
+		return false, nil
+	}
+
+	cfg, err := a.configManager.LoadConfig()
+	if err != nil {
+		return false, err
+	}
+
+	if a.appConfig != nil {
+		a.appConfig.WipeSecrets()
+	}
+	a.appConfig = cfg
+
+	if err := a.rcloneManager.GenerateRcloneConfig(cfg.DataSources); err != nil {
+		return true, fmt.Errorf("生成rclone配置失败: %v", err)
+	}
+
+	return true, nil
+}
+
+// SetAutoStart 设置开机自启动
+func (a *App) SetAutoStart(enabled bool) error {
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+
+	if a.appConfig == nil {
+		return fmt.Errorf("配置未初始化")
+	}
+
+	if enabled {
+		if err := a.autoStartManager.EnableAutoStart(); err != nil {
+			return err
+		}
+	} else {
+		if err := a.autoStartManager.DisableAutoStart(); err != nil {
+			return err
+		}
+	}
+
+	a.appConfig.AutoStart = enabled
+	return a.configManager.SaveConfig(a.appConfig)
+}
+
+// GetGistConfig 获取Gist配置
+func (a *App) GetGistConfig() (apiToken, gistID string, hasToken bool, err error) {
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+
+	if a.appConfig == nil {
+		return "", "", false, fmt.Errorf("配置未初始化")
+	}
+
+	// 只返回token是否存在和脱敏信息，不返回实际token
 	hasToken = a.appConfig.GistAPIToken != ""
 	apiToken = ""
 	if hasToken {
@@ -441,30 +1849,244 @@ func (a *App) monitorMountStatus() {
 	defer ticker.Stop()
 
 	for range ticker.C {
-		a.mountMutex.Lock()
-		mounts, err := a.rcloneManager.GetMounts()
-		if err == nil {
-			// 更新挂载状态
-			activeMounts := make(map[string]bool)
-			for _, mount := range mounts {
-				activeMounts[mount.Name] = true
-				if storedMount, exists := a.mountProcesses[mount.Name]; exists {
-					storedMount.Status = mount.Status
-				}
+		if err := a.refreshMounts(); err != nil {
+			fmt.Printf("读取挂载表失败: %v\n", err)
+		}
+	}
+}
+
+// refreshMounts 按系统挂载表更新挂载记录。
+// 挂载表中属于本应用数据源但没有记录的挂载（例如上次运行留下的）会被接管。
+func (a *App) refreshMounts() error {
+	mounts, err := a.rcloneManager.GetMounts()
+	if err != nil {
+		return err
+	}
+
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+
+	a.mountMutex.Lock()
+	defer a.mountMutex.Unlock()
+
+	// 更新挂载状态，按本地路径匹配，数据源重命名后依然有效
+	activeMounts := make(map[string]rclone.MountInfo)
+	for _, mount := range mounts {
+		activeMounts[mount.LocalPath] = mount
+	}
+
+	// 清理无效的挂载记录。受监督的挂载由监督负责重新挂载，放弃重新挂载的记录保留到用户卸载，
+	// 以便前端显示失败原因
+	known := make(map[string]bool)
+	for id, storedMount := range a.mountProcesses {
+		mount, active := activeMounts[storedMount.LocalPath]
+		if !active {
+			if _, supervised := a.supervisors[id]; !supervised && storedMount.Status != rclone.MountStatusFailed {
+				a.removeMountRecord(id)
+			}
+			continue
+		}
+		known[storedMount.LocalPath] = true
+		if storedMount.Status == rclone.MountStatusRestarting || storedMount.Status == rclone.MountStatusFailed {
+			continue
+		}
+		storedMount.Status = mount.Status
+		storedMount.PID = mount.PID
+	}
+
+	if a.appConfig == nil {
+		return nil
+	}
+
+	for _, mount := range mounts {
+		if known[mount.LocalPath] {
+			continue
+		}
+
+		ds, err := a.configManager.FindDataSource(a.appConfig, mount.SourceID)
+		if err != nil {
+			// 不是本应用创建的rclone挂载
+			continue
+		}
+
+		a.adoptMount(*ds, mount)
+	}
+
+	a.pruneMountLogs()
+	return nil
+}
+
+// adoptMount 为挂载表中已存在的挂载建立记录，匹配到保存的挂载定义时沿用其ID。
+// 调用方需持有 configMutex 和 mountMutex。
+func (a *App) adoptMount(ds config.DataSource, mount rclone.MountInfo) {
+	remotePath := strings.SplitN(mount.Remote, ":", 2)[1]
+	if ds.Type == config.TypeS3 && ds.S3 != nil && ds.S3.Bucket != "" {
+		remotePath = strings.TrimPrefix(remotePath, ds.S3.Bucket)
+	}
+	remotePath = "/" + strings.Trim(remotePath, "/")
+
+	mountInfo := mount
+	mountInfo.ID = uuid.NewString()
+	mountInfo.Name = ds.Name
+	mountInfo.Remote = remotePath
+	defaultPath, err := defaultMountPath(a.mountRoot(), ds.Name, remotePath)
+	mountInfo.ExplicitPath = err != nil || mount.LocalPath != defaultPath
+
+	for _, entry := range a.appConfig.Mounts {
+		if entry.SourceID == ds.ID && "/"+strings.Trim(entry.RemotePath, "/") == remotePath {
+			if _, exists := a.mountProcesses[entry.ID]; !exists {
+				mountInfo.ID = entry.ID
+				mountInfo.AutoMount = entry.AutoMount
 			}
+			break
+		}
+	}
+
+	a.mountProcesses[mountInfo.ID] = &mountInfo
+	a.rcloneManager.TrackMountLog(mountInfo.ID, rclone.RemoteName(ds), mountInfo.LocalPath)
+	a.startSupervisor(mountInfo.ID, rclone.RemoteName(ds), rclone.RemotePath(ds, remotePath), ds.EffectiveMountOptions())
+}
+
+// startSupervisor 开始监督挂载，调用方需持有 mountMutex
+func (a *App) startSupervisor(mountID, remoteName, remotePath string, opts config.MountOptions) {
+	a.stopSupervisor(mountID)
+
+	supervisor := &mountSupervisor{
+		stop:       make(chan struct{}),
+		remoteName: remoteName,
+		remotePath: remotePath,
+		opts:       opts,
+	}
+	a.supervisors[mountID] = supervisor
+	go a.superviseMount(mountID, supervisor)
+}
+
+// stopSupervisor 停止监督挂载并返回原监督信息，调用方需持有 mountMutex
+func (a *App) stopSupervisor(mountID string) *mountSupervisor {
+	supervisor, exists := a.supervisors[mountID]
+	if !exists {
+		return nil
+	}
+	close(supervisor.stop)
+	delete(a.supervisors, mountID)
+	return supervisor
+}
+
+// restoreSupervisor 卸载失败、挂载仍然存在时恢复监督，调用方需持有 mountMutex
+func (a *App) restoreSupervisor(mountID string, supervisor *mountSupervisor) {
+	if supervisor != nil {
+		a.startSupervisor(mountID, supervisor.remoteName, supervisor.remotePath, supervisor.opts)
+	}
+}
+
+// superviseMount 定期检查挂载，挂载消失或FUSE连接断开时清理挂载点并重新挂载。
+// rc服务进程本身由 rclone 包重启，rc服务重启后原有挂载失效，由这里重新挂载
+func (a *App) superviseMount(mountID string, supervisor *mountSupervisor) {
+	ticker := time.NewTicker(mountCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-supervisor.stop:
+			return
+		case <-ticker.C:
+		}
+
+		a.mountMutex.RLock()
+		mountInfo, exists := a.mountProcesses[mountID]
+		var localPath string
+		if exists {
+			localPath = mountInfo.LocalPath
+		}
+		a.mountMutex.RUnlock()
+		if !exists {
+			return
+		}
+
+		err := a.rcloneManager.CheckMount(localPath)
+		if err == nil {
+			continue
+		}
+
+		fmt.Printf("挂载 %s 已失效: %v，开始重新挂载\n", localPath, err)
+		if !a.restartMount(mountID, localPath, supervisor, err) {
+			return
+		}
+	}
+}
 
-			// 清理无效的挂载记录
-			for name := range a.mountProcesses {
-				if !activeMounts[name] {
-					delete(a.mountProcesses, name)
-				}
+// restartMount 以指数退避重新挂载，连续失败 mountMaxRestartAttempts 次后放弃。
+// 返回false表示已放弃或监督已停止
+func (a *App) restartMount(mountID, localPath string, supervisor *mountSupervisor, cause error) bool {
+	delay := mountRestartInitialDelay
+	lastErr := cause
+
+	for attempt := 1; attempt <= mountMaxRestartAttempts; attempt++ {
+		a.updateMountState(mountID, supervisor, rclone.MountStatusRestarting, lastErr)
+
+		select {
+		case <-supervisor.stop:
+			return false
+		case <-time.After(delay):
+		}
+
+		// 清理失效的挂载点
+		err := a.rcloneManager.Unmount(mountID, localPath)
+		if err == nil {
+			err = a.rcloneManager.Mount(mountID, supervisor.remoteName, supervisor.remotePath, localPath, supervisor.opts)
+		}
+		if err == nil {
+			if !a.updateMountState(mountID, supervisor, rclone.MountStatusMounted, nil) {
+				// 重新挂载期间用户卸载了该挂载
+				a.rcloneManager.Unmount(mountID, localPath)
+				return false
 			}
+			return true
 		}
-		a.mountMutex.Unlock()
+
+		fmt.Printf("重新挂载 %s 失败 (第%d次): %v\n", localPath, attempt, err)
+		lastErr = err
+		delay *= 2
+		if delay > mountRestartMaxDelay {
+			delay = mountRestartMaxDelay
+		}
+	}
+
+	a.mountMutex.Lock()
+	if a.supervisors[mountID] == supervisor {
+		delete(a.supervisors, mountID)
 	}
+	a.mountMutex.Unlock()
+
+	a.updateMountState(mountID, nil, rclone.MountStatusFailed, lastErr)
+	return false
 }
 
-// generateID 生成唯一ID
-func generateID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+// updateMountState 更新挂载状态并通知前端。进入重新挂载状态时累加重启次数。
+// supervisor 不为空时，监督已被停止或替换则不更新并返回false
+func (a *App) updateMountState(mountID string, supervisor *mountSupervisor, status string, err error) bool {
+	a.mountMutex.Lock()
+	mountInfo, exists := a.mountProcesses[mountID]
+	if !exists || (supervisor != nil && a.supervisors[mountID] != supervisor) {
+		a.mountMutex.Unlock()
+		return false
+	}
+
+	mountInfo.Status = status
+	if status == rclone.MountStatusRestarting {
+		mountInfo.Restarts++
+	}
+	if err != nil {
+		mountInfo.LastError = err.Error()
+	}
+	if status == rclone.MountStatusMounted {
+		mountInfo.PID = a.rcloneManager.DaemonPID()
+	}
+	event := *mountInfo
+	a.mountMutex.Unlock()
+
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, EventMountStatus, event)
+	}
+	return true
 }
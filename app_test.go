@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"rmount/config"
+	"rmount/keyring"
+	"rmount/rclone"
+)
+
+// memoryKeyStore 内存中的密钥存储，代替系统密钥环
+type memoryKeyStore struct {
+	secrets map[string][]byte
+}
+
+func newMemoryKeyStore() *memoryKeyStore {
+	return &memoryKeyStore{secrets: make(map[string][]byte)}
+}
+
+func (s *memoryKeyStore) Get(account string) ([]byte, error) {
+	secret, ok := s.secrets[account]
+	if !ok {
+		return nil, keyring.ErrNotFound
+	}
+	return append([]byte(nil), secret...), nil
+}
+
+func (s *memoryKeyStore) Has(account string) (bool, error) {
+	_, ok := s.secrets[account]
+	return ok, nil
+}
+
+func (s *memoryKeyStore) Set(account string, secret []byte) error {
+	s.secrets[account] = append([]byte(nil), secret...)
+	return nil
+}
+
+func (s *memoryKeyStore) Delete(account string) error {
+	delete(s.secrets, account)
+	return nil
+}
+
+func (s *memoryKeyStore) Name() string {
+	return "memory"
+}
+
+// newTestApp 创建不经过 startup 的App，使用临时配置目录和注入的密钥存储
+func newTestApp(t *testing.T, dir string, store keyring.KeyStore) *App {
+	t.Helper()
+
+	a := NewApp()
+	a.configDir = dir
+	a.keyStore = store
+	a.rcloneManager = rclone.NewRcloneManager(dir)
+	cm, err := config.NewConfigManager(dir, "")
+	if err != nil {
+		t.Fatalf("NewConfigManager: %v", err)
+	}
+	a.configManager = cm
+	return a
+}
+
+func TestRememberedPasswordLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	store := newMemoryKeyStore()
+
+	first := newTestApp(t, dir, store)
+	if err := first.SetMasterPassword("old-password"); err != nil {
+		t.Fatalf("SetMasterPassword: %v", err)
+	}
+	if err := first.SetRememberPassword(true); err != nil {
+		t.Fatalf("SetRememberPassword: %v", err)
+	}
+	if !first.IsPasswordRemembered() {
+		t.Fatal("password is not remembered")
+	}
+
+	// 重新启动后使用保存的密钥解锁
+	second := newTestApp(t, dir, store)
+	if err := second.unlockFromKeyStore(); err != nil {
+		t.Fatalf("unlockFromKeyStore: %v", err)
+	}
+	if !second.IsPasswordSet() {
+		t.Fatal("not unlocked from the key store")
+	}
+
+	// 修改密码后保存的密钥同步更新
+	if err := second.ChangeMasterPassword("old-password", "new-password"); err != nil {
+		t.Fatalf("ChangeMasterPassword: %v", err)
+	}
+	third := newTestApp(t, dir, store)
+	if err := third.unlockFromKeyStore(); err != nil {
+		t.Fatalf("unlockFromKeyStore after password change: %v", err)
+	}
+	if !third.IsPasswordSet() {
+		t.Fatal("not unlocked with the updated key")
+	}
+
+	// 其他机器修改了密码，保存的密钥失效后被删除
+	other, err := config.NewConfigManager(dir, "new-password")
+	if err != nil {
+		t.Fatalf("NewConfigManager: %v", err)
+	}
+	if _, err := other.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if err := other.ChangePassword("new-password", "other-password"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+
+	stale := newTestApp(t, dir, store)
+	if err := stale.unlockFromKeyStore(); err == nil {
+		t.Fatal("unlocked with a stale key")
+	}
+	if stale.IsPasswordSet() {
+		t.Fatal("stale key unlocked the config")
+	}
+	if _, err := store.Get(masterKeyAccount); err != keyring.ErrNotFound {
+		t.Fatalf("stale key was not deleted: %v", err)
+	}
+}
+
+func TestAutoMountSkipsAdoptedMount(t *testing.T) {
+	dir := t.TempDir()
+	a := newTestApp(t, dir, newMemoryKeyStore())
+	if err := a.SetMasterPassword("password"); err != nil {
+		t.Fatalf("SetMasterPassword: %v", err)
+	}
+
+	ds := config.DataSource{
+		ID:   "5f0c8a52-6f7d-4a7e-9d57-2f5a3c1b9e10",
+		Name: "backup",
+		Type: config.TypeWebDAV,
+		WebDAV: &config.WebDAVConfig{
+			URL: "https://dav.example.com",
+		},
+	}
+	entry := config.MountEntry{
+		ID:         "0b7f5a9e-3c1d-4e2f-8a6b-9c0d1e2f3a4b",
+		SourceID:   ds.ID,
+		RemotePath: "/docs",
+		AutoMount:  true,
+	}
+
+	a.configMutex.Lock()
+	a.appConfig.DataSources = append(a.appConfig.DataSources, ds)
+	a.appConfig.Mounts = append(a.appConfig.Mounts, entry)
+	a.configMutex.Unlock()
+
+	// rc服务在rmount重启后仍持有该挂载，refreshMounts 按保存的定义接管
+	a.configMutex.RLock()
+	a.mountMutex.Lock()
+	a.adoptMount(ds, rclone.MountInfo{
+		SourceID:  ds.ID,
+		Remote:    ds.ID + ":/docs",
+		LocalPath: filepath.Join(dir, "mnt", "backup", "docs"),
+		Status:    rclone.MountStatusMounted,
+	})
+	adopted, exists := a.mountProcesses[entry.ID]
+	a.mountMutex.Unlock()
+	a.configMutex.RUnlock()
+	if !exists {
+		t.Fatalf("mount was not adopted under the saved entry ID")
+	}
+
+	if status := a.runMountJob(entry); status != mountJobMounted {
+		t.Errorf("runMountJob status = %q, want %q", status, mountJobMounted)
+	}
+
+	a.mountMutex.Lock()
+	defer a.mountMutex.Unlock()
+	if a.mountProcesses[entry.ID] != adopted {
+		t.Error("adopted mount record was replaced")
+	}
+	a.stopSupervisor(entry.ID)
+}
+
+func TestDefaultMountPath(t *testing.T) {
+	root := "/data/mnt"
+	tests := []struct {
+		name       string
+		remotePath string
+		want       string
+		wantErr    bool
+	}{
+		{name: "photos", remotePath: "/", want: "/data/mnt/photos"},
+		{name: "photos", remotePath: "/2024/summer/", want: "/data/mnt/photos_2024_summer"},
+		{name: "..backup", remotePath: "", want: "/data/mnt/..backup"},
+		{name: "..", remotePath: "/", wantErr: true},
+		{name: ".", remotePath: "", wantErr: true},
+		{name: "...", remotePath: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := defaultMountPath(root, tt.name, tt.remotePath)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("defaultMountPath(%q, %q) = %q, want error", tt.name, tt.remotePath, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("defaultMountPath(%q, %q) = %q, %v, want %q", tt.name, tt.remotePath, got, err, tt.want)
+		}
+	}
+}
+
+// legacyConfigJSON 引入文件头之前的版本保存的配置内容
+const legacyConfigJSON = `{
+	"gistAPIToken": "",
+	"autoStart": false,
+	"mountDirectory": "/tmp/rmount",
+	"s3DataSources": [
+		{
+			"id": "1700000000000",
+			"name": "backup",
+			"endpoint": "https://s3.amazonaws.com",
+			"accessKey": "AKIAEXAMPLE",
+			"secretKey": "secret",
+			"region": "us-east-1",
+			"bucket": "my-bucket"
+		}
+	]
+}`
+
+// writeLegacyConfigFile 按引入文件头之前的格式写入配置: nonce | ciphertext，
+// 密钥为密码逐字节XOR得到的32字节，没有附加认证数据
+func writeLegacyConfigFile(t *testing.T, path, password, plaintext string) []byte {
+	t.Helper()
+
+	key := make([]byte, 32)
+	for i, b := range []byte(password) {
+		key[i%32] ^= b
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("NewGCM: %v", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatalf("nonce: %v", err)
+	}
+
+	data := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return data
+}
+
+func TestSetMasterPasswordMigratesLegacyConfig(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.enc")
+	legacy := writeLegacyConfigFile(t, configFile, "password", legacyConfigJSON)
+
+	// 密码错误时不能改动旧格式文件
+	wrong := newTestApp(t, dir, newMemoryKeyStore())
+	if err := wrong.SetMasterPassword("wrong-password"); err == nil {
+		t.Fatal("SetMasterPassword accepted a wrong password")
+	}
+	if wrong.IsPasswordSet() {
+		t.Fatal("wrong password unlocked the config")
+	}
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(data, legacy) {
+		t.Fatal("legacy config was modified after a wrong password")
+	}
+
+	a := newTestApp(t, dir, newMemoryKeyStore())
+	if err := a.SetMasterPassword("password"); err != nil {
+		t.Fatalf("SetMasterPassword: %v", err)
+	}
+
+	data, err = os.ReadFile(configFile)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("RMNT")) {
+		t.Fatal("config was not re-encrypted with a file header")
+	}
+	if a.configManager.NeedsMigration() {
+		t.Fatal("config still needs migration")
+	}
+
+	// 重新加密后的文件可以用同一密码加载，旧密钥不能再解密
+	reloaded, err := config.NewConfigManager(dir, "password")
+	if err != nil {
+		t.Fatalf("NewConfigManager: %v", err)
+	}
+	cfg, err := reloaded.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig after migration: %v", err)
+	}
+	if len(cfg.DataSources) != 1 || cfg.DataSources[0].Name != "backup" {
+		t.Fatalf("data sources after migration = %+v", cfg.DataSources)
+	}
+	if s3 := cfg.DataSources[0].S3; s3 == nil || s3.SecretKey != "secret" {
+		t.Errorf("secret key was not preserved: %+v", s3)
+	}
+	if cfg.SchemaVersion != config.CurrentSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", cfg.SchemaVersion, config.CurrentSchemaVersion)
+	}
+
+	stale := newTestApp(t, dir, newMemoryKeyStore())
+	if err := stale.SetMasterPassword("wrong-password"); err == nil {
+		t.Fatal("wrong password accepted after migration")
+	}
+}
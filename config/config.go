@@ -1,43 +1,158 @@
 package config
 
 import (
-	"crypto/aes"
+	"bytes"
 	"crypto/cipher"
-	"crypto/rand"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+
+	"github.com/google/uuid"
 )
 
+// ErrIncorrectPassword 主密码错误，无法解密配置
+var ErrIncorrectPassword = errors.New("主密码错误")
+
+// ErrKeyChanged 配置文件已被其他实例用新的主密码或盐重新加密，当前密钥无法解密
+var ErrKeyChanged = errors.New("配置文件已被其他密钥重新加密，请重新输入主密码")
+
 // S3Config 表示S3数据源配置
 type S3Config struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Endpoint    string `json:"endpoint"`
-	AccessKey   string `json:"accessKey"`
-	SecretKey   string `json:"secretKey"`
-	Region      string `json:"region"`
-	Bucket      string `json:"bucket"`
-	Description string `json:"description"`
+	Provider  string `json:"provider"`
+	Endpoint  string `json:"endpoint"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	Region    string `json:"region"`
+	Bucket    string `json:"bucket"`
+
+	// CredentialMode 凭证方式，见 CredentialStatic 等常量
+	CredentialMode string `json:"credentialMode"`
+	// SessionToken 临时凭证的会话令牌，仅 CredentialSession 使用
+	SessionToken string `json:"sessionToken"`
+	// Profile ~/.aws/config 中的配置名，仅 CredentialProfile 使用
+	Profile string `json:"profile"`
+	// AssumeRole 参数，仅 CredentialAssumeRole 使用，AccessKey/SecretKey 作为源凭证
+	RoleARN         string `json:"roleArn"`
+	ExternalID      string `json:"externalId"`
+	RoleSessionName string `json:"roleSessionName"`
+	// STSEndpoint 自定义STS地址，留空使用AWS默认地址
+	STSEndpoint string `json:"stsEndpoint"`
 }
 
+// S3凭证方式
+const (
+	// CredentialStatic 长期有效的 Access Key / Secret Key
+	CredentialStatic = "static"
+	// CredentialSession 带会话令牌的临时凭证
+	CredentialSession = "session"
+	// CredentialProfile 使用 ~/.aws/config 和 ~/.aws/credentials 中的命名配置
+	CredentialProfile = "profile"
+	// CredentialAssumeRole 使用源凭证调用STS AssumeRole，临时凭证过期前自动刷新
+	CredentialAssumeRole = "assume-role"
+)
+
 // AppConfig 应用配置
 type AppConfig struct {
+	SchemaVersion      int    `json:"schemaVersion"`
 	MasterPassword     string `json:"-"` // 不存储到文件中
 	GistAPIToken       string `json:"gistAPIToken,omitempty"`
 	GistID             string `json:"gistId,omitempty"`
 	AutoStart          bool   `json:"autoStart"`
 	MountDirectory     string `json:"mountDirectory"`
-	S3DataSources      []S3Config `json:"s3DataSources"`
+	DataSources        []DataSource `json:"dataSources"`
+	// Mounts 保存的挂载定义，包括解锁后自动挂载的条目
+	Mounts []MountEntry `json:"mounts"`
+
+	// AutoLockMinutes 空闲多少分钟后自动锁定，0 表示不自动锁定
+	AutoLockMinutes int `json:"autoLockMinutes"`
+	// LockMountPolicy 锁定时如何处理已挂载的数据源，见 LockKeepMounts 等常量
+	LockMountPolicy string `json:"lockMountPolicy"`
+
+	// RcloneLogLevel rclone日志级别，所有挂载共用一个rc服务，因此是全局设置。为空时使用 NOTICE
+	RcloneLogLevel string `json:"rcloneLogLevel"`
+}
+
+// 锁定时的挂载处理方式
+const (
+	// LockKeepMounts 保持挂载，rclone进程已读取配置，不受锁定影响
+	LockKeepMounts = "keep"
+	// LockUnmountAll 卸载所有数据源并删除磁盘上的rclone配置
+	LockUnmountAll = "unmount"
+)
+
+// rclone日志级别，对应 --log-level
+const (
+	LogLevelDebug  = "DEBUG"
+	LogLevelInfo   = "INFO"
+	LogLevelNotice = "NOTICE"
+	LogLevelError  = "ERROR"
+)
+
+// ValidLogLevel 检查rclone日志级别，空字符串表示默认级别
+func ValidLogLevel(level string) bool {
+	switch level {
+	case "", LogLevelDebug, LogLevelInfo, LogLevelNotice, LogLevelError:
+		return true
+	}
+	return false
+}
+
+// MaxAutoLockMinutes 自动锁定时间上限
+const MaxAutoLockMinutes = 24 * 60
+
+// WipeSecrets 清除配置中的密钥和密码。
+// Go字符串不可原地修改，这里丢弃所有引用，由GC回收底层内存。
+func (c *AppConfig) WipeSecrets() {
+	c.MasterPassword = ""
+	c.GistAPIToken = ""
+
+	for i := range c.DataSources {
+		ds := &c.DataSources[i]
+		if ds.S3 != nil {
+			ds.S3.AccessKey = ""
+			ds.S3.SecretKey = ""
+			ds.S3.SessionToken = ""
+			ds.S3.ExternalID = ""
+		}
+		if ds.WebDAV != nil {
+			ds.WebDAV.Password = ""
+		}
+		if ds.SFTP != nil {
+			ds.SFTP.Password = ""
+		}
+		if ds.Rclone != nil {
+			ds.Rclone.Options = nil
+		}
+	}
+	c.DataSources = nil
+}
+
+// NewDataSourceID 生成数据源ID。
+// rclone remote 名称和挂载记录都使用ID，显示名称可以随意修改。
+func NewDataSourceID() string {
+	return uuid.NewString()
 }
 
 // ConfigManager 配置管理器
 type ConfigManager struct {
 	configFile string
+	backupDir  string
 	gcm        cipher.AEAD
+	params     *kdfParams
+	key        []byte
+	header     []byte
+
+	// 旧版XOR密钥，仅在配置文件仍为旧格式时用于解密
+	legacyGCM cipher.AEAD
+
+	// 多进程写入协调用的锁文件
+	lockFile string
+	// 本进程最近一次读写后配置文件的状态，用于发现外部修改
+	diskState os.FileInfo
 }
 
 // NewConfigManager 创建配置管理器
@@ -50,6 +165,8 @@ func NewConfigManager(configDir string, masterPassword string) (*ConfigManager,
 
 	cm := &ConfigManager{
 		configFile: configFile,
+		backupDir:  filepath.Join(configDir, "backups"),
+		lockFile:   filepath.Join(configDir, "config.lock"),
 	}
 
 	// 如果主密码不为空，初始化加密
@@ -62,33 +179,99 @@ func NewConfigManager(configDir string, masterPassword string) (*ConfigManager,
 	return cm, nil
 }
 
-// initEncryption 初始化加密
-func (cm *ConfigManager) initEncryption(password string) error {
-	// 使用密码的SHA256哈希作为密钥
-	key := cm.deriveKey(password)
+// NewConfigManagerWithKey 使用已派生的密钥创建配置管理器，用于从本机密钥环自动解锁。
+// 密钥必须与现有配置文件头中的派生参数匹配，调用方应通过 LoadConfig 验证。
+func NewConfigManagerWithKey(configDir string, key []byte) (*ConfigManager, error) {
+	cm, err := NewConfigManager(configDir, "")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(cm.configFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %v", err)
+	}
 
-	block, err := aes.NewCipher(key)
+	params, header, _, err := parseFileHeader(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	gcm, err := newGCM(key)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("初始化加密失败: %v", err)
 	}
 
 	cm.gcm = gcm
-	return nil
+	cm.params = params
+	cm.key = append([]byte(nil), key...)
+	cm.header = header
+	return cm, nil
+}
+
+// Close 清零派生密钥并丢弃加密器，之后需要重新输入主密码才能解密配置
+func (cm *ConfigManager) Close() {
+	zero(cm.key)
+	cm.key = nil
+	cm.gcm = nil
+	cm.legacyGCM = nil
+	cm.params = nil
+	cm.header = nil
 }
 
-// deriveKey 从密码派生密钥
-func (cm *ConfigManager) deriveKey(password string) []byte {
-	// 简单的密钥派生，实际应用中应该使用更安全的方法
-	key := make([]byte, 32)
-	for i, b := range []byte(password) {
-		key[i%32] ^= b
+// zero 将字节切片清零
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
 	}
-	return key
+}
+
+// DerivedKey 返回当前使用的派生密钥副本，用于保存到本机密钥环
+func (cm *ConfigManager) DerivedKey() []byte {
+	return append([]byte(nil), cm.key...)
+}
+
+// initEncryption 初始化加密
+func (cm *ConfigManager) initEncryption(password string) error {
+	data, err := ioutil.ReadFile(cm.configFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("读取配置文件失败: %v", err)
+	}
+
+	var params *kdfParams
+	if err == nil && hasFileHeader(data) {
+		// 沿用已有文件的盐和派生参数
+		params, _, _, err = parseFileHeader(data)
+		if err != nil {
+			return err
+		}
+	} else {
+		if err == nil && len(data) > 0 {
+			// 旧格式文件，保留旧密钥用于读取，保存时自动迁移到新格式
+			legacyGCM, err := newGCM(legacyDeriveKey(password))
+			if err != nil {
+				return err
+			}
+			cm.legacyGCM = legacyGCM
+		}
+
+		params, err = newKDFParams()
+		if err != nil {
+			return err
+		}
+	}
+
+	key := params.deriveKey(password)
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	cm.gcm = gcm
+	cm.params = params
+	cm.key = key
+	cm.header = params.header()
+	return nil
 }
 
 // LoadConfig 加载配置
@@ -96,13 +279,14 @@ func (cm *ConfigManager) LoadConfig() (*AppConfig, error) {
 	if _, err := os.Stat(cm.configFile); os.IsNotExist(err) {
 		// 配置文件不存在，返回默认配置
 		return &AppConfig{
+			SchemaVersion:  CurrentSchemaVersion,
 			AutoStart:      false,
-			MountDirectory: filepath.Join(os.Getenv("HOME"), "mounts"),
-			S3DataSources:  []S3Config{},
+			MountDirectory: DefaultMountDirectory(),
+			DataSources:    []DataSource{},
 		}, nil
 	}
 
-	data, err := ioutil.ReadFile(cm.configFile)
+	data, info, err := cm.readConfigFile()
 	if err != nil {
 		return nil, fmt.Errorf("读取配置文件失败: %v", err)
 	}
@@ -111,24 +295,65 @@ func (cm *ConfigManager) LoadConfig() (*AppConfig, error) {
 		return nil, fmt.Errorf("加密未初始化，无法解密配置")
 	}
 
-	// 解密数据
-	nonceSize := cm.gcm.NonceSize()
-	if len(data) < nonceSize {
-		return nil, fmt.Errorf("配置文件格式错误")
+	plaintext, err := cm.decrypt(data)
+	if err != nil {
+		return nil, err
 	}
+	// 解析后立即清零明文，避免在内存中残留
+	defer zero(plaintext)
 
-	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
-	plaintext, err := cm.gcm.Open(nil, nonce, ciphertext, nil)
+	config, err := ParseConfig(plaintext)
 	if err != nil {
-		return nil, fmt.Errorf("解密配置失败: %v", err)
+		return nil, err
+	}
+
+	cm.diskState = info
+	return config, nil
+}
+
+// decrypt 解密配置文件内容，兼容旧格式
+func (cm *ConfigManager) decrypt(data []byte) ([]byte, error) {
+	gcm := cm.gcm
+	var header, body []byte
+
+	if hasFileHeader(data) {
+		_, fileHeader, rest, err := parseFileHeader(data)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(fileHeader, cm.header) {
+			return nil, ErrKeyChanged
+		}
+		header, body = fileHeader, rest
+	} else {
+		if cm.legacyGCM == nil {
+			return nil, fmt.Errorf("配置文件格式错误")
+		}
+		gcm, body = cm.legacyGCM, data
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(body) < nonceSize {
+		return nil, fmt.Errorf("配置文件格式错误")
 	}
 
-	var config AppConfig
-	if err := json.Unmarshal(plaintext, &config); err != nil {
-		return nil, fmt.Errorf("解析配置失败: %v", err)
+	nonce, ciphertext := body[:nonceSize], body[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		// GCM认证失败几乎总是意味着密码错误
+		return nil, ErrIncorrectPassword
 	}
 
-	return &config, nil
+	return plaintext, nil
+}
+
+// NeedsMigration 检查磁盘上的配置文件是否仍为旧的加密格式
+func (cm *ConfigManager) NeedsMigration() bool {
+	data, err := ioutil.ReadFile(cm.configFile)
+	if err != nil || len(data) == 0 {
+		return false
+	}
+	return !hasFileHeader(data)
 }
 
 // SaveConfig 保存配置
@@ -137,64 +362,219 @@ func (cm *ConfigManager) SaveConfig(config *AppConfig) error {
 		return fmt.Errorf("加密未初始化，无法加密配置")
 	}
 
-	// 序列化配置
-	data, err := json.Marshal(config)
+	// 序列化配置，写入版本号时使用副本，不修改调用方持有的配置
+	snapshot := *config
+	snapshot.SchemaVersion = CurrentSchemaVersion
+	data, err := json.Marshal(&snapshot)
 	if err != nil {
 		return fmt.Errorf("序列化配置失败: %v", err)
 	}
 
 	// 加密数据
-	nonce := make([]byte, cm.gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return fmt.Errorf("生成nonce失败: %v", err)
+	out, err := seal(cm.gcm, cm.header, data)
+	if err != nil {
+		return err
+	}
+
+	err = cm.withFileLock(func() error {
+		// 其他实例在本次加载后写过配置，直接覆盖会丢失对方的修改
+		if cm.ChangedOnDisk() {
+			return ErrConfigChanged
+		}
+
+		// 保留当前版本的快照后原子替换
+		if err := cm.snapshotCurrent(); err != nil {
+			return err
+		}
+
+		if err := writeFileAtomic(cm.configFile, out, 0600); err != nil {
+			return fmt.Errorf("写入配置文件失败: %v", err)
+		}
+
+		cm.recordDiskState()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 文件已是新格式，不再需要旧密钥
+	cm.legacyGCM = nil
+	return nil
+}
+
+// VerifyPassword 校验主密码是否与当前使用的密钥一致
+func (cm *ConfigManager) VerifyPassword(password string) error {
+	if cm.gcm == nil || cm.params == nil {
+		return fmt.Errorf("加密未初始化")
+	}
+
+	if subtle.ConstantTimeCompare(cm.params.deriveKey(password), cm.key) != 1 {
+		return ErrIncorrectPassword
+	}
+	return nil
+}
+
+// ChangePassword 校验旧密码后使用新密码重新加密配置文件。
+// 原文件会先保存为 config.enc.bak（仍由旧密码加密），新文件通过临时文件原子替换。
+func (cm *ConfigManager) ChangePassword(oldPassword, newPassword string) error {
+	if cm.gcm == nil {
+		return fmt.Errorf("加密未初始化，无法修改密码")
+	}
+
+	if newPassword == "" {
+		return fmt.Errorf("新密码不能为空")
+	}
+
+	if err := cm.VerifyPassword(oldPassword); err != nil {
+		return err
+	}
+
+	params, err := newKDFParams()
+	if err != nil {
+		return err
+	}
+
+	key := params.deriveKey(newPassword)
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
 	}
+	header := params.header()
+
+	err = cm.withFileLock(func() error {
+		if cm.ChangedOnDisk() {
+			return ErrConfigChanged
+		}
+
+		data, err := ioutil.ReadFile(cm.configFile)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取配置文件失败: %v", err)
+		}
+
+		plaintext, err := cm.decrypt(data)
+		if err != nil {
+			return err
+		}
 
-	ciphertext := cm.gcm.Seal(nonce, nonce, data, nil)
+		out, err := seal(gcm, header, plaintext)
+		if err != nil {
+			return err
+		}
+
+		if err := writeFileAtomic(cm.BackupFile(), data, 0600); err != nil {
+			return fmt.Errorf("备份配置文件失败: %v", err)
+		}
+
+		if err := writeFileAtomic(cm.configFile, out, 0600); err != nil {
+			return fmt.Errorf("写入配置文件失败: %v", err)
+		}
 
-	// 写入文件
-	if err := ioutil.WriteFile(cm.configFile, ciphertext, 0600); err != nil {
-		return fmt.Errorf("写入配置文件失败: %v", err)
+		cm.recordDiskState()
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
+	cm.gcm = gcm
+	cm.params = params
+	cm.key = key
+	cm.header = header
+	cm.legacyGCM = nil
 	return nil
 }
 
+// BackupFile 返回修改密码前保存的配置备份路径
+func (cm *ConfigManager) BackupFile() string {
+	return cm.configFile + ".bak"
+}
+
 // IsEncryptionInitialized 检查加密是否已初始化
 func (cm *ConfigManager) IsEncryptionInitialized() bool {
 	return cm.gcm != nil
 }
 
-// AddS3DataSource 添加S3数据源
-func (cm *ConfigManager) AddS3DataSource(config *AppConfig, s3Config S3Config) error {
+// AddDataSource 添加数据源
+func (cm *ConfigManager) AddDataSource(config *AppConfig, ds DataSource) error {
+	if err := ValidateDataSource(ds); err != nil {
+		return err
+	}
+
 	// 检查名称是否已存在
-	for _, ds := range config.S3DataSources {
-		if ds.Name == s3Config.Name {
-			return fmt.Errorf("数据源名称 '%s' 已存在", s3Config.Name)
+	for _, existing := range config.DataSources {
+		if existing.Name == ds.Name {
+			return fmt.Errorf("数据源名称 '%s' 已存在", ds.Name)
 		}
 	}
 
-	config.S3DataSources = append(config.S3DataSources, s3Config)
+	config.DataSources = append(config.DataSources, ds)
 	return nil
 }
 
-// RemoveS3DataSource 删除S3数据源
-func (cm *ConfigManager) RemoveS3DataSource(config *AppConfig, name string) error {
-	for i, ds := range config.S3DataSources {
-		if ds.Name == name {
-			config.S3DataSources = append(config.S3DataSources[:i], config.S3DataSources[i+1:]...)
-			return nil
+// FindDataSource 按ID查找数据源
+func (cm *ConfigManager) FindDataSource(config *AppConfig, id string) (*DataSource, error) {
+	for i := range config.DataSources {
+		if config.DataSources[i].ID == id {
+			return &config.DataSources[i], nil
 		}
 	}
-	return fmt.Errorf("未找到名为 '%s' 的数据源", name)
+	return nil, fmt.Errorf("未找到ID为 '%s' 的数据源", id)
 }
 
-// UpdateS3DataSource 更新S3数据源
-func (cm *ConfigManager) UpdateS3DataSource(config *AppConfig, s3Config S3Config) error {
-	for i, ds := range config.S3DataSources {
-		if ds.Name == s3Config.Name {
-			config.S3DataSources[i] = s3Config
+// RemoveDataSource 删除数据源
+func (cm *ConfigManager) RemoveDataSource(config *AppConfig, id string) error {
+	for i, ds := range config.DataSources {
+		if ds.ID == id {
+			config.DataSources = append(config.DataSources[:i], config.DataSources[i+1:]...)
+			removeSourceMounts(config, id)
 			return nil
 		}
 	}
-	return fmt.Errorf("未找到名为 '%s' 的数据源", s3Config.Name)
-}
\ No newline at end of file
+	return fmt.Errorf("未找到ID为 '%s' 的数据源", id)
+}
+
+// UpdateDataSource 更新数据源，提交的密钥为空时沿用原有的值
+func (cm *ConfigManager) UpdateDataSource(config *AppConfig, ds DataSource) error {
+	index := -1
+	for i, existing := range config.DataSources {
+		if existing.ID == ds.ID {
+			index = i
+		} else if existing.Name == ds.Name {
+			return fmt.Errorf("数据源名称 '%s' 已存在", ds.Name)
+		}
+	}
+
+	if index < 0 {
+		return fmt.Errorf("未找到ID为 '%s' 的数据源", ds.ID)
+	}
+
+	// 空密钥表示保持不变
+	keepSecrets(config.DataSources[index], &ds)
+
+	if err := ValidateDataSource(ds); err != nil {
+		return err
+	}
+
+	config.DataSources[index] = ds
+	return nil
+}
+
+// RenameDataSource 重命名数据源
+func (cm *ConfigManager) RenameDataSource(config *AppConfig, id, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("数据源名称不能为空")
+	}
+
+	ds, err := cm.FindDataSource(config, id)
+	if err != nil {
+		return err
+	}
+
+	renamed := *ds
+	renamed.Name = newName
+	return cm.UpdateDataSource(config, renamed)
+}
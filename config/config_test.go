@@ -0,0 +1,34 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadConfigAfterExternalPasswordChange(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := NewConfigManager(dir, "old-password")
+	if err != nil {
+		t.Fatalf("NewConfigManager: %v", err)
+	}
+	cfg := &AppConfig{SchemaVersion: CurrentSchemaVersion, DataSources: []DataSource{}}
+	if err := first.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+
+	second, err := NewConfigManager(dir, "old-password")
+	if err != nil {
+		t.Fatalf("NewConfigManager: %v", err)
+	}
+	if _, err := second.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if err := second.ChangePassword("old-password", "new-password"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+
+	if _, err := first.LoadConfig(); !errors.Is(err, ErrKeyChanged) {
+		t.Fatalf("LoadConfig after external password change: got %v, want ErrKeyChanged", err)
+	}
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// 加密配置文件格式（版本1）:
+//
+//	magic(4) | version(1) | kdf(1) | time(4) | memory(4) | threads(1) | saltLen(1) | salt | nonce | ciphertext
+//
+// 文件头同时作为 AES-GCM 的附加认证数据，篡改KDF参数会导致解密失败。
+// 没有文件头的文件是早期使用XOR派生密钥加密的旧格式。
+var fileMagic = []byte("RMNT")
+
+const (
+	fileFormatVersion = 1
+
+	kdfArgon2id = 1
+
+	defaultKDFTime    = 3
+	defaultKDFMemory  = 64 * 1024 // KiB
+	defaultKDFThreads = 4
+	kdfSaltSize       = 16
+	kdfKeySize        = 32
+
+	// 参数上限，防止恶意文件头或导入文件导致过量内存分配和长时间计算。
+	// 默认参数远低于上限，上限只用于拒绝异常文件
+	maxKDFTime    = 10
+	maxKDFMemory  = 256 * 1024 // KiB
+	maxKDFThreads = 16
+)
+
+// kdfParams 密钥派生参数
+type kdfParams struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	Salt    []byte
+}
+
+// newKDFParams 使用默认参数和随机盐创建派生参数
+func newKDFParams() (*kdfParams, error) {
+	salt := make([]byte, kdfSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("生成盐失败: %v", err)
+	}
+
+	return &kdfParams{
+		Time:    defaultKDFTime,
+		Memory:  defaultKDFMemory,
+		Threads: defaultKDFThreads,
+		Salt:    salt,
+	}, nil
+}
+
+// deriveKey 使用Argon2id从密码派生密钥
+func (p *kdfParams) deriveKey(password string) []byte {
+	return argon2.IDKey([]byte(password), p.Salt, p.Time, p.Memory, p.Threads, kdfKeySize)
+}
+
+// header 序列化文件头
+func (p *kdfParams) header() []byte {
+	var buf bytes.Buffer
+	buf.Write(fileMagic)
+	buf.WriteByte(fileFormatVersion)
+	buf.WriteByte(kdfArgon2id)
+	binary.Write(&buf, binary.BigEndian, p.Time)
+	binary.Write(&buf, binary.BigEndian, p.Memory)
+	buf.WriteByte(p.Threads)
+	buf.WriteByte(byte(len(p.Salt)))
+	buf.Write(p.Salt)
+	return buf.Bytes()
+}
+
+// hasFileHeader 判断数据是否带有版本化文件头
+func hasFileHeader(data []byte) bool {
+	return bytes.HasPrefix(data, fileMagic)
+}
+
+// parseFileHeader 解析文件头，返回派生参数、原始文件头和剩余的密文部分
+func parseFileHeader(data []byte) (*kdfParams, []byte, []byte, error) {
+	const fixedSize = 4 + 1 + 1 + 4 + 4 + 1 + 1
+	if !hasFileHeader(data) || len(data) < fixedSize {
+		return nil, nil, nil, fmt.Errorf("配置文件格式错误")
+	}
+
+	version := data[4]
+	if version != fileFormatVersion {
+		return nil, nil, nil, fmt.Errorf("不支持的配置文件版本: %d", version)
+	}
+
+	if data[5] != kdfArgon2id {
+		return nil, nil, nil, fmt.Errorf("不支持的密钥派生算法: %d", data[5])
+	}
+
+	p := &kdfParams{
+		Time:    binary.BigEndian.Uint32(data[6:10]),
+		Memory:  binary.BigEndian.Uint32(data[10:14]),
+		Threads: data[14],
+	}
+	saltLen := int(data[15])
+
+	if p.Time == 0 || p.Time > maxKDFTime || p.Memory == 0 || p.Memory > maxKDFMemory ||
+		p.Threads == 0 || p.Threads > maxKDFThreads || saltLen == 0 {
+		return nil, nil, nil, fmt.Errorf("配置文件密钥派生参数无效")
+	}
+
+	headerSize := fixedSize + saltLen
+	if len(data) < headerSize {
+		return nil, nil, nil, fmt.Errorf("配置文件格式错误")
+	}
+
+	p.Salt = append([]byte(nil), data[fixedSize:headerSize]...)
+	return p, data[:headerSize], data[headerSize:], nil
+}
+
+// legacyDeriveKey 旧版本使用的XOR密钥派生，仅用于读取并迁移旧配置
+func legacyDeriveKey(password string) []byte {
+	key := make([]byte, 32)
+	for i, b := range []byte(password) {
+		key[i%32] ^= b
+	}
+	return key
+}
+
+// seal 加密数据并在前面加上文件头和nonce
+func seal(gcm cipher.AEAD, header, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("生成nonce失败: %v", err)
+	}
+
+	out := append([]byte(nil), header...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plaintext, header), nil
+}
+
+// newGCM 使用密钥创建AES-GCM
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
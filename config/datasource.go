@@ -0,0 +1,188 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 数据源类型
+const (
+	TypeS3     = "s3"
+	TypeWebDAV = "webdav"
+	TypeSFTP   = "sftp"
+	// TypeRclone 任意rclone后端，选项原样写入rclone.conf
+	TypeRclone = "rclone"
+)
+
+// DataSource 数据源，Type 决定使用哪一组连接参数
+type DataSource struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+
+	S3     *S3Config     `json:"s3,omitempty"`
+	WebDAV *WebDAVConfig `json:"webdav,omitempty"`
+	SFTP   *SFTPConfig   `json:"sftp,omitempty"`
+	Rclone *RcloneConfig `json:"rclone,omitempty"`
+
+	// MountOptions 为空时使用 DefaultMountOptions
+	MountOptions *MountOptions `json:"mountOptions,omitempty"`
+}
+
+// WebDAVConfig WebDAV数据源配置
+type WebDAVConfig struct {
+	URL string `json:"url"`
+	// Vendor 对应rclone的vendor选项: nextcloud, owncloud, sharepoint, other
+	Vendor   string `json:"vendor"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+// SFTPConfig SFTP数据源配置
+type SFTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	// KeyFile 私钥文件路径，与密码二选一
+	KeyFile string `json:"keyFile"`
+	// KnownHostsFile 为空时不校验主机密钥，与rclone默认行为一致
+	KnownHostsFile string `json:"knownHostsFile"`
+}
+
+// RcloneConfig 任意rclone remote配置
+type RcloneConfig struct {
+	// Backend rclone后端类型，例如 drive、onedrive、b2
+	Backend string            `json:"backend"`
+	Options map[string]string `json:"options"`
+}
+
+var (
+	rcloneBackendPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+	rcloneOptionPattern  = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+	webdavVendors = []string{"", "nextcloud", "owncloud", "sharepoint", "sharepoint-ntlm", "other"}
+)
+
+// ValidateDataSource 校验数据源定义，返回 *ValidationError 或 nil
+func ValidateDataSource(ds DataSource) error {
+	verr := &ValidationError{}
+
+	validateName(verr, ds.Name)
+
+	switch ds.Type {
+	case TypeS3:
+		if ds.S3 == nil {
+			verr.add("type", CodeRequired, "缺少S3连接参数")
+		} else {
+			validateS3(verr, *ds.S3)
+		}
+	case TypeWebDAV:
+		if ds.WebDAV == nil {
+			verr.add("type", CodeRequired, "缺少WebDAV连接参数")
+		} else {
+			validateWebDAV(verr, *ds.WebDAV)
+		}
+	case TypeSFTP:
+		if ds.SFTP == nil {
+			verr.add("type", CodeRequired, "缺少SFTP连接参数")
+		} else {
+			validateSFTP(verr, *ds.SFTP)
+		}
+	case TypeRclone:
+		if ds.Rclone == nil {
+			verr.add("type", CodeRequired, "缺少rclone连接参数")
+		} else {
+			validateRclone(verr, *ds.Rclone)
+		}
+	default:
+		verr.add("type", CodeInvalidValue, "不支持的数据源类型: "+ds.Type)
+	}
+
+	if ds.MountOptions != nil {
+		validateMountOptions(verr, *ds.MountOptions)
+	}
+
+	if len(verr.Fields) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// validateWebDAV 校验WebDAV连接参数
+func validateWebDAV(verr *ValidationError, c WebDAVConfig) {
+	if c.URL == "" {
+		verr.add("url", CodeRequired, "URL 不能为空")
+	} else {
+		validateURL(verr, "url", "URL", c.URL)
+	}
+
+	valid := false
+	for _, v := range webdavVendors {
+		if c.Vendor == v {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		verr.add("vendor", CodeInvalidValue, "不支持的WebDAV服务类型: "+c.Vendor)
+	}
+
+	if hasControlChars(c.User) {
+		verr.add("user", CodeInvalidChars, "用户名不能包含换行等控制字符")
+	}
+	if hasControlChars(c.Password) {
+		verr.add("password", CodeInvalidChars, "密码不能包含换行等控制字符")
+	}
+}
+
+// validateSFTP 校验SFTP连接参数
+func validateSFTP(verr *ValidationError, c SFTPConfig) {
+	if strings.TrimSpace(c.Host) == "" {
+		verr.add("host", CodeRequired, "主机不能为空")
+	} else if hasControlChars(c.Host) || strings.ContainsAny(c.Host, " /") {
+		verr.add("host", CodeInvalidChars, "主机名格式无效")
+	}
+
+	if c.Port < 0 || c.Port > 65535 {
+		verr.add("port", CodeInvalidValue, "端口必须在1到65535之间，留空使用22")
+	}
+
+	if strings.TrimSpace(c.User) == "" {
+		verr.add("user", CodeRequired, "用户名不能为空")
+	} else if hasControlChars(c.User) {
+		verr.add("user", CodeInvalidChars, "用户名不能包含换行等控制字符")
+	}
+
+	if c.Password == "" && c.KeyFile == "" {
+		verr.add("password", CodeRequired, "密码和私钥文件至少填写一项")
+	}
+
+	if hasControlChars(c.Password) {
+		verr.add("password", CodeInvalidChars, "密码不能包含换行等控制字符")
+	}
+	if hasControlChars(c.KeyFile) {
+		verr.add("keyFile", CodeInvalidChars, "私钥文件路径不能包含换行等控制字符")
+	}
+	if hasControlChars(c.KnownHostsFile) {
+		verr.add("knownHostsFile", CodeInvalidChars, "known_hosts文件路径不能包含换行等控制字符")
+	}
+}
+
+// validateRclone 校验任意rclone后端的选项
+func validateRclone(verr *ValidationError, c RcloneConfig) {
+	if c.Backend == "" {
+		verr.add("backend", CodeRequired, "rclone后端类型不能为空")
+	} else if !rcloneBackendPattern.MatchString(c.Backend) {
+		verr.add("backend", CodeInvalidChars, "rclone后端类型只能包含小写字母和数字")
+	}
+
+	for key, value := range c.Options {
+		if key == "type" || !rcloneOptionPattern.MatchString(key) {
+			verr.add("options", CodeInvalidChars, "无效的rclone选项名: "+key)
+		} else if hasControlChars(value) {
+			verr.add("options", CodeInvalidChars, "rclone选项 "+key+" 的值不能包含换行等控制字符")
+		}
+	}
+}
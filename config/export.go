@@ -0,0 +1,301 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	bundleFormat  = "rmount-config-bundle"
+	bundleVersion = 1
+)
+
+// ImportMode 导入方式
+type ImportMode string
+
+const (
+	// ImportModeReplace 用导入的配置整体替换当前配置
+	ImportModeReplace ImportMode = "replace"
+	// ImportModeMerge 只添加当前配置中不存在的数据源，其余设置保持不变
+	ImportModeMerge ImportMode = "merge"
+)
+
+// bundleKDF 导出文件中记录的密钥派生参数
+type bundleKDF struct {
+	Algorithm string `json:"algorithm"`
+	Time      uint32 `json:"time"`
+	Memory    uint32 `json:"memory"`
+	Threads   uint8  `json:"threads"`
+	Salt      string `json:"salt"`
+}
+
+// bundle 导出文件结构，除密文外均为明文，方便识别文件来源和版本
+type bundle struct {
+	Format        string    `json:"format"`
+	Version       int       `json:"version"`
+	SchemaVersion int       `json:"schemaVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	KDF           bundleKDF `json:"kdf"`
+	Nonce         string    `json:"nonce"`
+	Ciphertext    string    `json:"ciphertext"`
+}
+
+// DataSourceCollision 合并导入时名称冲突的数据源
+type DataSourceCollision struct {
+	Name string `json:"name"`
+	// Identical 为true表示导入的数据源与现有数据源完全相同
+	Identical bool `json:"identical"`
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Mode       ImportMode            `json:"mode"`
+	Added      []string              `json:"added"`
+	Collisions []DataSourceCollision `json:"collisions"`
+}
+
+// ExportToFile 使用独立的导出口令加密整个配置并写入文件
+func ExportToFile(path string, config *AppConfig, passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("导出口令不能为空")
+	}
+
+	params, err := newKDFParams()
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(params.deriveKey(passphrase))
+	if err != nil {
+		return err
+	}
+
+	// 调用方只持有读锁，在副本上设置版本号
+	snapshot := *config
+	snapshot.SchemaVersion = CurrentSchemaVersion
+	plaintext, err := json.Marshal(&snapshot)
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %v", err)
+	}
+
+	header := params.header()
+	sealed, err := seal(gcm, header, plaintext)
+	if err != nil {
+		return err
+	}
+	nonce := sealed[len(header) : len(header)+gcm.NonceSize()]
+	ciphertext := sealed[len(header)+gcm.NonceSize():]
+
+	b := bundle{
+		Format:        bundleFormat,
+		Version:       bundleVersion,
+		SchemaVersion: CurrentSchemaVersion,
+		CreatedAt:     time.Now(),
+		KDF: bundleKDF{
+			Algorithm: "argon2id",
+			Time:      params.Time,
+			Memory:    params.Memory,
+			Threads:   params.Threads,
+			Salt:      base64.StdEncoding.EncodeToString(params.Salt),
+		},
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化导出文件失败: %v", err)
+	}
+
+	if err := writeFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("写入导出文件失败: %v", err)
+	}
+
+	return nil
+}
+
+// ImportFromFile 读取并解密导出文件
+func ImportFromFile(path string, passphrase string) (*AppConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取导入文件失败: %v", err)
+	}
+
+	var b bundle
+	if err := json.Unmarshal(data, &b); err != nil || b.Format != bundleFormat {
+		return nil, fmt.Errorf("不是有效的rmount配置导出文件")
+	}
+
+	if b.Version != bundleVersion {
+		return nil, fmt.Errorf("不支持的导出文件版本: %d", b.Version)
+	}
+
+	if b.KDF.Algorithm != "argon2id" {
+		return nil, fmt.Errorf("不支持的密钥派生算法: %s", b.KDF.Algorithm)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(b.KDF.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("导出文件格式错误")
+	}
+	nonce, err := base64.StdEncoding.DecodeString(b.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("导出文件格式错误")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(b.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("导出文件格式错误")
+	}
+
+	params := &kdfParams{
+		Time:    b.KDF.Time,
+		Memory:  b.KDF.Memory,
+		Threads: b.KDF.Threads,
+		Salt:    salt,
+	}
+
+	// 复用配置文件头的校验逻辑检查参数范围
+	if _, _, _, err := parseFileHeader(params.header()); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(params.deriveKey(passphrase))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("导出文件格式错误")
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, params.header())
+	if err != nil {
+		return nil, fmt.Errorf("导出口令错误或文件已损坏")
+	}
+
+	config, err := ParseConfig(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sanitizeImport(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// sanitizeImport 校验导入的数据源和挂载定义。导入文件不可信，数据源字段会写入 rclone.conf，
+// 必须与界面添加的数据源经过相同的校验；非UUID的ID重新分配，挂载定义随之更新
+func sanitizeImport(config *AppConfig) error {
+	sourceIDs := make(map[string]string, len(config.DataSources))
+	seen := make(map[string]bool, len(config.DataSources))
+
+	for i := range config.DataSources {
+		ds := &config.DataSources[i]
+
+		oldID := ds.ID
+		if _, err := uuid.Parse(ds.ID); err != nil || seen[ds.ID] {
+			ds.ID = NewDataSourceID()
+		}
+		seen[ds.ID] = true
+		if _, exists := sourceIDs[oldID]; !exists {
+			sourceIDs[oldID] = ds.ID
+		}
+
+		if err := ValidateDataSource(*ds); err != nil {
+			return fmt.Errorf("导入的数据源 '%s' 无效: %w", ds.Name, err)
+		}
+	}
+
+	mounts := make([]MountEntry, 0, len(config.Mounts))
+	mountIDs := make(map[string]bool, len(config.Mounts))
+	for _, entry := range config.Mounts {
+		sourceID, ok := sourceIDs[entry.SourceID]
+		if !ok {
+			// 引用不存在的数据源的挂载定义无法使用
+			continue
+		}
+		entry.SourceID = sourceID
+
+		if _, err := uuid.Parse(entry.ID); err != nil || mountIDs[entry.ID] {
+			entry.ID = uuid.NewString()
+		}
+		mountIDs[entry.ID] = true
+
+		if entry.LocalPath != "" {
+			if err := ValidateMountPath("localPath", entry.LocalPath); err != nil {
+				return fmt.Errorf("导入的挂载定义无效: %w", err)
+			}
+		}
+		mounts = append(mounts, entry)
+	}
+	config.Mounts = mounts
+
+	return nil
+}
+
+// MergeConfig 将导入配置中的数据源合并到当前配置，同名数据源保留现有版本并报告冲突。
+// 新增数据源的挂载定义一并合并，数据源ID重新分配时随之更新
+func MergeConfig(dst, src *AppConfig) *ImportResult {
+	result := &ImportResult{
+		Mode:       ImportModeMerge,
+		Added:      []string{},
+		Collisions: []DataSourceCollision{},
+	}
+
+	existing := make(map[string]DataSource, len(dst.DataSources))
+	ids := make(map[string]bool, len(dst.DataSources))
+	for _, ds := range dst.DataSources {
+		existing[ds.Name] = ds
+		ids[ds.ID] = true
+	}
+
+	// added 导入配置中的数据源ID到合并后ID的映射
+	added := make(map[string]string)
+	for _, ds := range src.DataSources {
+		if current, ok := existing[ds.Name]; ok {
+			result.Collisions = append(result.Collisions, DataSourceCollision{
+				Name:      ds.Name,
+				Identical: reflect.DeepEqual(current, ds),
+			})
+			continue
+		}
+
+		// 来自同一份配置的不同副本可能带有相同ID
+		oldID := ds.ID
+		if ids[ds.ID] {
+			ds.ID = NewDataSourceID()
+		}
+
+		dst.DataSources = append(dst.DataSources, ds)
+		existing[ds.Name] = ds
+		ids[ds.ID] = true
+		added[oldID] = ds.ID
+		result.Added = append(result.Added, ds.Name)
+	}
+
+	mountIDs := make(map[string]bool, len(dst.Mounts))
+	for _, entry := range dst.Mounts {
+		mountIDs[entry.ID] = true
+	}
+	for _, entry := range src.Mounts {
+		sourceID, ok := added[entry.SourceID]
+		if !ok {
+			continue
+		}
+		entry.SourceID = sourceID
+		if mountIDs[entry.ID] {
+			entry.ID = uuid.NewString()
+		}
+		mountIDs[entry.ID] = true
+		dst.Mounts = append(dst.Mounts, entry)
+	}
+
+	return result
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func webdavSource(id, name string) DataSource {
+	return DataSource{
+		ID:   id,
+		Name: name,
+		Type: TypeWebDAV,
+		WebDAV: &WebDAVConfig{
+			URL:    "https://dav.example.com/remote.php/webdav",
+			Vendor: "nextcloud",
+			User:   "alice",
+		},
+	}
+}
+
+func TestImportRejectsInvalidSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.rmount")
+	src := webdavSource(NewDataSourceID(), "dav")
+	src.WebDAV.URL = "https://dav.example.com\n[injected]"
+	if err := ExportToFile(path, &AppConfig{DataSources: []DataSource{src}}, "passphrase"); err != nil {
+		t.Fatalf("ExportToFile: %v", err)
+	}
+
+	if _, err := ImportFromFile(path, "passphrase"); err == nil {
+		t.Fatal("ImportFromFile accepted a data source with a control character in its URL")
+	}
+}
+
+func TestImportReassignsNonUUIDIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.rmount")
+	exported := &AppConfig{
+		DataSources: []DataSource{webdavSource("../../etc", "dav")},
+		Mounts: []MountEntry{
+			{ID: "../mount", SourceID: "../../etc", LocalPath: "/mnt/dav"},
+			{ID: uuid.NewString(), SourceID: "missing", LocalPath: "/mnt/missing"},
+		},
+	}
+	if err := ExportToFile(path, exported, "passphrase"); err != nil {
+		t.Fatalf("ExportToFile: %v", err)
+	}
+
+	imported, err := ImportFromFile(path, "passphrase")
+	if err != nil {
+		t.Fatalf("ImportFromFile: %v", err)
+	}
+
+	sourceID := imported.DataSources[0].ID
+	if _, err := uuid.Parse(sourceID); err != nil {
+		t.Fatalf("data source ID %q was not reassigned", sourceID)
+	}
+	if len(imported.Mounts) != 1 {
+		t.Fatalf("got %d mount entries, want 1 (entries of unknown sources are dropped)", len(imported.Mounts))
+	}
+	entry := imported.Mounts[0]
+	if _, err := uuid.Parse(entry.ID); err != nil || strings.Contains(entry.ID, "/") {
+		t.Errorf("mount entry ID %q was not reassigned", entry.ID)
+	}
+	if entry.SourceID != sourceID {
+		t.Errorf("mount entry source = %q, want %q", entry.SourceID, sourceID)
+	}
+}
+
+func TestMergeConfigCarriesMounts(t *testing.T) {
+	sharedID := NewDataSourceID()
+	dst := &AppConfig{
+		DataSources: []DataSource{webdavSource(sharedID, "existing")},
+		Mounts:      []MountEntry{{ID: uuid.NewString(), SourceID: sharedID, LocalPath: "/mnt/existing"}},
+	}
+	src := &AppConfig{
+		DataSources: []DataSource{
+			webdavSource(NewDataSourceID(), "existing"),
+			webdavSource(sharedID, "copy"),
+		},
+		Mounts: []MountEntry{
+			{ID: uuid.NewString(), SourceID: sharedID, LocalPath: "/mnt/copy"},
+		},
+	}
+	src.Mounts = append(src.Mounts, MountEntry{ID: dst.Mounts[0].ID, SourceID: sharedID, LocalPath: "/mnt/copy2"})
+
+	result := MergeConfig(dst, src)
+	if len(result.Added) != 1 || result.Added[0] != "copy" {
+		t.Fatalf("added = %v, want [copy]", result.Added)
+	}
+
+	copyID := dst.DataSources[1].ID
+	if copyID == sharedID {
+		t.Fatal("colliding data source ID was not reassigned")
+	}
+	if len(dst.Mounts) != 3 {
+		t.Fatalf("got %d mount entries, want 3", len(dst.Mounts))
+	}
+	ids := make(map[string]bool)
+	for _, entry := range dst.Mounts {
+		if ids[entry.ID] {
+			t.Errorf("duplicate mount entry ID %s", entry.ID)
+		}
+		ids[entry.ID] = true
+	}
+	for _, entry := range dst.Mounts[1:] {
+		if entry.SourceID != copyID {
+			t.Errorf("merged entry %s points to %q, want %q", entry.LocalPath, entry.SourceID, copyID)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// ErrConfigChanged 配置文件在本次加载之后被其他进程修改，保存会覆盖对方的修改
+var ErrConfigChanged = errors.New("配置文件已被其他rmount实例修改，请等待重新加载后再试")
+
+// withFileLock 持有配置目录的排他建议锁执行 fn，多个rmount进程的写操作因此串行执行。
+// 建议锁只约束同样加锁的进程，读取依赖原子替换保证完整性。
+func (cm *ConfigManager) withFileLock(fn func() error) error {
+	f, err := os.OpenFile(cm.lockFile, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("打开锁文件失败: %v", err)
+	}
+	defer f.Close()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("锁定配置文件失败: %v", err)
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	return fn()
+}
+
+// readConfigFile 读取配置文件，同时记录读取时的文件状态
+func (cm *ConfigManager) readConfigFile() ([]byte, os.FileInfo, error) {
+	f, err := os.Open(cm.configFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	// 对同一个文件句柄取状态，原子替换发生在读取期间也不会错配
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := make([]byte, info.Size())
+	if _, err := f.ReadAt(data, 0); err != nil && info.Size() > 0 {
+		return nil, nil, err
+	}
+
+	return data, info, nil
+}
+
+// recordDiskState 记录本进程最近一次读写后配置文件的状态
+func (cm *ConfigManager) recordDiskState() {
+	info, err := os.Stat(cm.configFile)
+	if err != nil {
+		cm.diskState = nil
+		return
+	}
+	cm.diskState = info
+}
+
+// ChangedOnDisk 检查配置文件在本进程最近一次读写之后是否被其他进程替换或修改
+func (cm *ConfigManager) ChangedOnDisk() bool {
+	info, err := os.Stat(cm.configFile)
+	if err != nil {
+		// 文件不存在时只有之前见过它才算变化
+		return os.IsNotExist(err) && cm.diskState != nil
+	}
+
+	if cm.diskState == nil {
+		return true
+	}
+
+	return !os.SameFile(info, cm.diskState) ||
+		!info.ModTime().Equal(cm.diskState.ModTime()) ||
+		info.Size() != cm.diskState.Size()
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// CurrentSchemaVersion 当前配置结构版本，修改 AppConfig 或 S3Config 的持久化格式时递增，
+// 并在 migrations 中注册对应的迁移步骤
+const CurrentSchemaVersion = 5
+
+// migration 将配置从 from 版本升级到 from+1 版本。
+// 迁移直接操作JSON对象，旧字段在结构体中删除后依然可以读取。
+type migration struct {
+	from    int
+	migrate func(raw map[string]interface{}) error
+}
+
+// migrations 按版本顺序注册的迁移链
+var migrations = []migration{
+	{from: 0, migrate: migrateV0ToV1},
+	{from: 1, migrate: migrateV1ToV2},
+	{from: 2, migrate: migrateV2ToV3},
+	{from: 3, migrate: migrateV3ToV4},
+	{from: 4, migrate: migrateV4ToV5},
+}
+
+// migrateV0ToV1 版本0为引入schemaVersion之前的格式，结构与版本1一致
+func migrateV0ToV1(raw map[string]interface{}) error {
+	if _, ok := raw["s3DataSources"]; !ok {
+		raw["s3DataSources"] = []interface{}{}
+	}
+	return nil
+}
+
+// migrateV1ToV2 数据源改为以UUID作为稳定标识，为缺失、重复或旧的时间戳ID重新分配
+func migrateV1ToV2(raw map[string]interface{}) error {
+	sources, _ := raw["s3DataSources"].([]interface{})
+	seen := make(map[string]bool)
+
+	for _, item := range sources {
+		ds, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("数据源格式错误")
+		}
+
+		id, _ := ds["id"].(string)
+		if _, err := uuid.Parse(id); err != nil || seen[id] {
+			id = NewDataSourceID()
+			ds["id"] = id
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// migrateV2ToV3 引入服务商字段，旧数据源一直按AWS方式生成rclone配置，保持不变
+func migrateV2ToV3(raw map[string]interface{}) error {
+	sources, _ := raw["s3DataSources"].([]interface{})
+	for _, item := range sources {
+		ds, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("数据源格式错误")
+		}
+
+		if provider, _ := ds["provider"].(string); provider == "" {
+			ds["provider"] = ProviderAWS
+		}
+	}
+
+	return nil
+}
+
+// migrateV3ToV4 s3DataSources 改为带类型的 dataSources，S3连接参数移入 s3 字段
+func migrateV3ToV4(raw map[string]interface{}) error {
+	sources, _ := raw["s3DataSources"].([]interface{})
+	dataSources := make([]interface{}, 0, len(sources))
+
+	for _, item := range sources {
+		old, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("数据源格式错误")
+		}
+
+		ds := map[string]interface{}{
+			"type": TypeS3,
+		}
+		for _, key := range []string{"id", "name", "description"} {
+			if v, ok := old[key]; ok {
+				ds[key] = v
+			}
+			delete(old, key)
+		}
+		ds["s3"] = old
+
+		dataSources = append(dataSources, ds)
+	}
+
+	delete(raw, "s3DataSources")
+	raw["dataSources"] = dataSources
+	return nil
+}
+
+// migrateV4ToV5 引入S3凭证方式，旧数据源均为静态密钥
+func migrateV4ToV5(raw map[string]interface{}) error {
+	sources, _ := raw["dataSources"].([]interface{})
+	for _, item := range sources {
+		ds, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("数据源格式错误")
+		}
+
+		s3, ok := ds["s3"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if mode, _ := s3["credentialMode"].(string); mode == "" {
+			s3["credentialMode"] = CredentialStatic
+		}
+	}
+
+	return nil
+}
+
+// ParseConfig 解析配置JSON，并依次执行迁移升级到当前版本
+func ParseConfig(data []byte) (*AppConfig, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %v", err)
+	}
+
+	if err := migrateRaw(raw); err != nil {
+		return nil, err
+	}
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %v", err)
+	}
+
+	var config AppConfig
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %v", err)
+	}
+
+	return &config, nil
+}
+
+// migrateRaw 对JSON对象执行迁移链
+func migrateRaw(raw map[string]interface{}) error {
+	version := 0
+	if v, ok := raw["schemaVersion"]; ok {
+		f, ok := v.(float64)
+		if !ok || f < 0 || f != float64(int(f)) {
+			return fmt.Errorf("配置版本号无效: %v", v)
+		}
+		version = int(f)
+	}
+
+	if version > CurrentSchemaVersion {
+		return fmt.Errorf("配置版本 %d 高于当前程序支持的版本 %d，请升级rmount", version, CurrentSchemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.from != version {
+			continue
+		}
+		if err := m.migrate(raw); err != nil {
+			return fmt.Errorf("配置从版本 %d 迁移失败: %v", m.from, err)
+		}
+		version = m.from + 1
+		raw["schemaVersion"] = version
+	}
+
+	if version != CurrentSchemaVersion {
+		return fmt.Errorf("缺少从版本 %d 开始的配置迁移", version)
+	}
+
+	return nil
+}
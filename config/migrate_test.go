@@ -0,0 +1,84 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// v0Config 引入schemaVersion之前的配置，数据源使用时间戳ID
+const v0Config = `{
+	"gistAPIToken": "",
+	"autoStart": false,
+	"mountDirectory": "/tmp/rmount",
+	"s3DataSources": [
+		{
+			"id": "1700000000000",
+			"name": "backup",
+			"endpoint": "https://s3.amazonaws.com",
+			"accessKey": "AKIAEXAMPLE",
+			"secretKey": "secret",
+			"region": "us-east-1",
+			"bucket": "my-bucket",
+			"description": "old source"
+		},
+		{
+			"id": "1700000000000",
+			"name": "duplicate",
+			"endpoint": "https://s3.amazonaws.com",
+			"accessKey": "AKIAEXAMPLE2",
+			"secretKey": "secret2",
+			"region": "eu-west-1",
+			"bucket": "other-bucket"
+		}
+	]
+}`
+
+func TestParseConfigMigratesV0(t *testing.T) {
+	cfg, err := ParseConfig([]byte(v0Config))
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+
+	if cfg.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", cfg.SchemaVersion, CurrentSchemaVersion)
+	}
+	if cfg.MountDirectory != "/tmp/rmount" {
+		t.Errorf("MountDirectory = %q", cfg.MountDirectory)
+	}
+	if len(cfg.DataSources) != 2 {
+		t.Fatalf("len(DataSources) = %d, want 2", len(cfg.DataSources))
+	}
+
+	seen := make(map[string]bool)
+	for _, ds := range cfg.DataSources {
+		if _, err := uuid.Parse(ds.ID); err != nil {
+			t.Errorf("data source %q ID %q is not a UUID", ds.Name, ds.ID)
+		}
+		if seen[ds.ID] {
+			t.Errorf("duplicate data source ID %q", ds.ID)
+		}
+		seen[ds.ID] = true
+
+		if ds.Type != TypeS3 {
+			t.Errorf("data source %q type = %q, want %q", ds.Name, ds.Type, TypeS3)
+		}
+		if ds.S3 == nil {
+			t.Fatalf("data source %q has no s3 config", ds.Name)
+		}
+		if ds.S3.Provider != ProviderAWS {
+			t.Errorf("data source %q provider = %q, want %q", ds.Name, ds.S3.Provider, ProviderAWS)
+		}
+		if ds.S3.CredentialMode != CredentialStatic {
+			t.Errorf("data source %q credentialMode = %q, want %q", ds.Name, ds.S3.CredentialMode, CredentialStatic)
+		}
+	}
+
+	first := cfg.DataSources[0]
+	if first.Name != "backup" || first.Description != "old source" {
+		t.Errorf("first data source = %q/%q", first.Name, first.Description)
+	}
+	if first.S3.Bucket != "my-bucket" || first.S3.AccessKey != "AKIAEXAMPLE" || first.S3.SecretKey != "secret" {
+		t.Errorf("first s3 config not carried over: %+v", *first.S3)
+	}
+}
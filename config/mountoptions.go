@@ -0,0 +1,110 @@
+package config
+
+import (
+	"regexp"
+)
+
+// VFS缓存模式，对应rclone的 --vfs-cache-mode
+const (
+	CacheModeOff     = "off"
+	CacheModeMinimal = "minimal"
+	CacheModeWrites  = "writes"
+	CacheModeFull    = "full"
+)
+
+// 挂载参数上限
+const (
+	maxCacheSizeMB      = 10 * 1024 * 1024 // 10 TiB
+	maxCacheAgeMinutes  = 30 * 24 * 60     // 30天
+	maxBufferSizeMB     = 1024
+	maxDirCacheTimeSecs = 24 * 60 * 60
+	maxReadyTimeoutSecs = 10 * 60
+	maxUnixID           = 1<<31 - 1
+)
+
+// MountOptions 数据源的挂载参数，由rclone包转换为 rclone mount 的命令行参数。
+// 数值为0表示使用rclone默认值。
+type MountOptions struct {
+	CacheMode string `json:"cacheMode"`
+	ReadOnly  bool   `json:"readOnly"`
+
+	// UID/GID 为空时使用当前用户
+	UID *int `json:"uid,omitempty"`
+	GID *int `json:"gid,omitempty"`
+	// Umask 八进制权限掩码，例如 022
+	Umask string `json:"umask"`
+
+	CacheMaxSizeMB      int `json:"cacheMaxSizeMB"`
+	CacheMaxAgeMinutes  int `json:"cacheMaxAgeMinutes"`
+	BufferSizeMB        int `json:"bufferSizeMB"`
+	DirCacheTimeSeconds int `json:"dirCacheTimeSeconds"`
+
+	// AllowOther 允许其他用户访问挂载点，Linux需要在 /etc/fuse.conf 中启用 user_allow_other
+	AllowOther    bool `json:"allowOther"`
+	AllowNonEmpty bool `json:"allowNonEmpty"`
+	// NetworkMode 以网络驱动器而非本地磁盘的形式挂载
+	NetworkMode bool `json:"networkMode"`
+
+	// ReadyTimeoutSeconds 等待挂载点可以访问的时间，为0时使用 DefaultReadyTimeoutSeconds。
+	// 只由rmount使用，不传给rclone
+	ReadyTimeoutSeconds int `json:"readyTimeoutSeconds"`
+}
+
+// DefaultReadyTimeoutSeconds 默认的挂载就绪等待时间
+const DefaultReadyTimeoutSeconds = 30
+
+// DefaultMountOptions 未单独配置的数据源使用的挂载参数，与之前固定的参数一致
+func DefaultMountOptions() MountOptions {
+	return MountOptions{
+		CacheMode:     CacheModeFull,
+		AllowOther:    true,
+		AllowNonEmpty: true,
+	}
+}
+
+// EffectiveMountOptions 返回数据源实际使用的挂载参数
+func (ds DataSource) EffectiveMountOptions() MountOptions {
+	if ds.MountOptions != nil {
+		return *ds.MountOptions
+	}
+	return DefaultMountOptions()
+}
+
+var umaskPattern = regexp.MustCompile(`^0?[0-7]{3}$`)
+
+// validateMountOptions 校验挂载参数范围
+func validateMountOptions(verr *ValidationError, o MountOptions) {
+	// 为空时使用默认的 full，与 MountRCOptions 一致
+	switch o.CacheMode {
+	case "", CacheModeOff, CacheModeMinimal, CacheModeWrites, CacheModeFull:
+	default:
+		verr.add("mountOptions.cacheMode", CodeInvalidValue, "不支持的缓存模式: "+o.CacheMode)
+	}
+
+	if o.UID != nil && (*o.UID < 0 || *o.UID > maxUnixID) {
+		verr.add("mountOptions.uid", CodeInvalidValue, "UID 超出范围")
+	}
+	if o.GID != nil && (*o.GID < 0 || *o.GID > maxUnixID) {
+		verr.add("mountOptions.gid", CodeInvalidValue, "GID 超出范围")
+	}
+
+	if o.Umask != "" && !umaskPattern.MatchString(o.Umask) {
+		verr.add("mountOptions.umask", CodeInvalidFormat, "umask 必须是三位八进制数，例如 022")
+	}
+
+	if o.CacheMaxSizeMB < 0 || o.CacheMaxSizeMB > maxCacheSizeMB {
+		verr.add("mountOptions.cacheMaxSizeMB", CodeInvalidValue, "缓存上限必须在0到10 TiB之间")
+	}
+	if o.CacheMaxAgeMinutes < 0 || o.CacheMaxAgeMinutes > maxCacheAgeMinutes {
+		verr.add("mountOptions.cacheMaxAgeMinutes", CodeInvalidValue, "缓存保留时间必须在0到30天之间")
+	}
+	if o.BufferSizeMB < 0 || o.BufferSizeMB > maxBufferSizeMB {
+		verr.add("mountOptions.bufferSizeMB", CodeInvalidValue, "读缓冲区必须在0到1024 MiB之间")
+	}
+	if o.DirCacheTimeSeconds < 0 || o.DirCacheTimeSeconds > maxDirCacheTimeSecs {
+		verr.add("mountOptions.dirCacheTimeSeconds", CodeInvalidValue, "目录缓存时间必须在0到86400秒之间")
+	}
+	if o.ReadyTimeoutSeconds < 0 || o.ReadyTimeoutSeconds > maxReadyTimeoutSecs {
+		verr.add("mountOptions.readyTimeoutSeconds", CodeInvalidValue, "挂载等待时间必须在0到600秒之间")
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// protectedMountDirs 不允许作为挂载路径或挂载根目录的系统目录
+var protectedMountDirs = []string{
+	"/bin", "/boot", "/dev", "/etc", "/lib", "/lib64", "/proc", "/root",
+	"/run", "/sbin", "/sys", "/usr", "/var", "/System", "/Library", "/Applications",
+}
+
+// DefaultMountDirectory 未设置挂载根目录时使用的默认值
+func DefaultMountDirectory() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, "mounts")
+}
+
+// ExpandPath 展开路径开头的 ~ 并清理路径
+func ExpandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		path = filepath.Join(homeDir, path[1:])
+	}
+	if path == "" {
+		return ""
+	}
+	return filepath.Clean(path)
+}
+
+// ValidateMountPath 校验挂载路径或挂载根目录，路径需已展开。field 为出错时报告的字段名
+func ValidateMountPath(field, path string) error {
+	verr := &ValidationError{}
+
+	homeDir, _ := os.UserHomeDir()
+
+	switch {
+	case path == "":
+		verr.add(field, CodeRequired, "挂载路径不能为空")
+	case hasControlChars(path):
+		verr.add(field, CodeInvalidChars, "挂载路径不能包含换行等控制字符")
+	case !filepath.IsAbs(path):
+		verr.add(field, CodeInvalidFormat, "挂载路径必须是绝对路径")
+	case path == "/" || path == homeDir:
+		verr.add(field, CodeInvalidValue, "不能挂载到根目录或用户主目录")
+	case isProtectedMountDir(path) && !strings.HasPrefix(path, homeDir+string(filepath.Separator)):
+		verr.add(field, CodeInvalidValue, "不能挂载到系统目录: "+path)
+	default:
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			verr.add(field, CodeInvalidValue, "挂载路径已存在且不是目录")
+		}
+	}
+
+	if len(verr.Fields) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// isProtectedMountDir 检查路径是否为系统目录或位于系统目录下
+func isProtectedMountDir(path string) bool {
+	for _, dir := range protectedMountDirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+)
+
+// MountEntry 保存在配置中的挂载定义，ID同时作为挂载记录的ID
+type MountEntry struct {
+	ID         string `json:"id"`
+	SourceID   string `json:"sourceId"`
+	RemotePath string `json:"remotePath"`
+	// LocalPath 为空时挂载到挂载根目录下
+	LocalPath string `json:"localPath"`
+	// AutoMount 解锁配置后自动挂载，按 Mounts 中的顺序启动
+	AutoMount bool `json:"autoMount"`
+}
+
+// SaveMountEntry 添加或按ID更新挂载定义
+func (cm *ConfigManager) SaveMountEntry(config *AppConfig, entry MountEntry) error {
+	if _, err := cm.FindDataSource(config, entry.SourceID); err != nil {
+		return err
+	}
+
+	if entry.LocalPath != "" {
+		entry.LocalPath = ExpandPath(entry.LocalPath)
+		if err := ValidateMountPath("localPath", entry.LocalPath); err != nil {
+			return err
+		}
+	}
+
+	for i, existing := range config.Mounts {
+		if existing.ID == entry.ID {
+			config.Mounts[i] = entry
+			return nil
+		}
+	}
+
+	config.Mounts = append(config.Mounts, entry)
+	return nil
+}
+
+// RemoveMountEntry 删除挂载定义
+func (cm *ConfigManager) RemoveMountEntry(config *AppConfig, id string) error {
+	for i, entry := range config.Mounts {
+		if entry.ID == id {
+			config.Mounts = append(config.Mounts[:i], config.Mounts[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("未找到ID为 '%s' 的挂载", id)
+}
+
+// removeSourceMounts 删除数据源的所有挂载定义
+func removeSourceMounts(config *AppConfig, sourceID string) {
+	mounts := config.Mounts[:0]
+	for _, entry := range config.Mounts {
+		if entry.SourceID != sourceID {
+			mounts = append(mounts, entry)
+		}
+	}
+	config.Mounts = mounts
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"strings"
+)
+
+// 内置的S3兼容服务商ID
+const (
+	ProviderAWS     = "aws"
+	ProviderMinIO   = "minio"
+	ProviderR2      = "cloudflare-r2"
+	ProviderWasabi  = "wasabi"
+	ProviderCeph    = "ceph"
+	ProviderAliyun  = "aliyun-oss"
+	ProviderTencent = "tencent-cos"
+	ProviderOther   = "other"
+)
+
+// Provider S3兼容服务商的接入参数
+type Provider struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// RcloneProvider 写入rclone.conf的provider值
+	RcloneProvider string `json:"rcloneProvider"`
+	// ForcePathStyle 使用 endpoint/bucket 形式的路径访问，而不是 bucket.endpoint 虚拟主机形式
+	ForcePathStyle bool `json:"forcePathStyle"`
+	// EndpointTemplate 按区域生成默认Endpoint，%s 替换为区域。为空表示没有默认值
+	EndpointTemplate string `json:"endpointTemplate"`
+	// RequiresEndpoint 没有默认Endpoint时必须由用户填写
+	RequiresEndpoint bool   `json:"requiresEndpoint"`
+	DefaultRegion    string `json:"defaultRegion"`
+}
+
+// providers 内置服务商目录
+var providers = []Provider{
+	{
+		ID:             ProviderAWS,
+		Name:           "Amazon S3",
+		RcloneProvider: "AWS",
+		DefaultRegion:  "us-east-1",
+	},
+	{
+		ID:               ProviderMinIO,
+		Name:             "MinIO",
+		RcloneProvider:   "Minio",
+		ForcePathStyle:   true,
+		RequiresEndpoint: true,
+		DefaultRegion:    "us-east-1",
+	},
+	{
+		ID:               ProviderR2,
+		Name:             "Cloudflare R2",
+		RcloneProvider:   "Cloudflare",
+		ForcePathStyle:   true,
+		RequiresEndpoint: true, // https://<账户ID>.r2.cloudflarestorage.com
+		DefaultRegion:    "auto",
+	},
+	{
+		ID:               ProviderWasabi,
+		Name:             "Wasabi",
+		RcloneProvider:   "Wasabi",
+		EndpointTemplate: "https://s3.%s.wasabisys.com",
+		DefaultRegion:    "us-east-1",
+	},
+	{
+		ID:               ProviderCeph,
+		Name:             "Ceph",
+		RcloneProvider:   "Ceph",
+		ForcePathStyle:   true,
+		RequiresEndpoint: true,
+		DefaultRegion:    "us-east-1",
+	},
+	{
+		ID:               ProviderAliyun,
+		Name:             "阿里云 OSS",
+		RcloneProvider:   "Alibaba",
+		EndpointTemplate: "https://oss-%s.aliyuncs.com",
+		DefaultRegion:    "cn-hangzhou",
+	},
+	{
+		ID:               ProviderTencent,
+		Name:             "腾讯云 COS",
+		RcloneProvider:   "TencentCOS",
+		EndpointTemplate: "https://cos.%s.myqcloud.com",
+		DefaultRegion:    "ap-guangzhou",
+	},
+	{
+		ID:               ProviderOther,
+		Name:             "其他S3兼容服务",
+		RcloneProvider:   "Other",
+		ForcePathStyle:   true,
+		RequiresEndpoint: true,
+		DefaultRegion:    "us-east-1",
+	},
+}
+
+// Providers 返回内置服务商目录
+func Providers() []Provider {
+	return append([]Provider(nil), providers...)
+}
+
+// LookupProvider 按ID查找服务商，空ID视为AWS以兼容旧配置
+func LookupProvider(id string) (Provider, bool) {
+	if id == "" {
+		id = ProviderAWS
+	}
+	for _, p := range providers {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Provider{}, false
+}
+
+// ProviderInfo 返回数据源对应的服务商，未知服务商按通用S3兼容服务处理
+func (c S3Config) ProviderInfo() Provider {
+	if p, ok := LookupProvider(c.Provider); ok {
+		return p
+	}
+	p, _ := LookupProvider(ProviderOther)
+	return p
+}
+
+// ResolvedEndpoint 返回实际使用的Endpoint，未填写时使用服务商按区域生成的默认值
+func (c S3Config) ResolvedEndpoint() string {
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+
+	p := c.ProviderInfo()
+	if p.EndpointTemplate == "" || c.Region == "" {
+		return ""
+	}
+	return strings.Replace(p.EndpointTemplate, "%s", c.Region, 1)
+}
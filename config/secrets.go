@@ -0,0 +1,174 @@
+package config
+
+import (
+	"strings"
+)
+
+// MaskedDataSource 返回给前端的数据源，所有密钥字段置空，只在 MaskedSecrets 中保留掩码
+type MaskedDataSource struct {
+	DataSource
+	// MaskedSecrets 已设置的密钥字段及其掩码，键与 Secrets 返回的键一致
+	MaskedSecrets map[string]string `json:"maskedSecrets"`
+}
+
+// publicOptions 已知不含密钥的rclone选项。rclone后端的密钥选项名没有统一规则
+// （例如 B2 的 key、azureblob 的 sas_url、drive 的 service_account_credentials、sftp 的 key_pem），
+// 因此除这些选项外，其余选项的值一律视为密钥
+var publicOptions = map[string]bool{
+	"provider":            true,
+	"env_auth":            true,
+	"region":              true,
+	"endpoint":            true,
+	"location_constraint": true,
+	"acl":                 true,
+	"storage_class":       true,
+	"force_path_style":    true,
+	"url":                 true,
+	"vendor":              true,
+	"user":                true,
+	"host":                true,
+	"port":                true,
+	"account":             true,
+	"client_id":           true,
+	"tenant":              true,
+	"scope":               true,
+	"root_folder_id":      true,
+	"team_drive":          true,
+	"drive_id":            true,
+	"drive_type":          true,
+	"key_file":            true,
+	"pubkey_file":         true,
+	"known_hosts_file":    true,
+	"shell_type":          true,
+	"chunk_size":          true,
+	"upload_cutoff":       true,
+	"hard_delete":         true,
+	"disable_hashcheck":   true,
+}
+
+// isSecretOption 判断rclone选项是否为密钥，不在 publicOptions 中的选项都视为密钥
+func isSecretOption(key string) bool {
+	return !publicOptions[strings.ToLower(key)]
+}
+
+// secretMask 已设置的密钥统一显示的掩码
+const secretMask = "已设置"
+
+// minSuffixLength 显示后缀的最短密钥长度，较短的密钥显示后缀会泄露过多信息
+const minSuffixLength = 20
+
+// MaskSecret 返回密钥的掩码。密码只显示固定掩码，足够长的访问密钥显示后4位以便区分
+func MaskSecret(field, secret string) string {
+	if field == "password" || len(secret) < minSuffixLength {
+		return secretMask
+	}
+	return "..." + secret[len(secret)-4:]
+}
+
+// Secrets 返回数据源中的所有密钥，键为字段名，rclone选项使用 options.<选项名>
+func (ds DataSource) Secrets() map[string]string {
+	secrets := make(map[string]string)
+	set := func(field, value string) {
+		if value != "" {
+			secrets[field] = value
+		}
+	}
+
+	if ds.S3 != nil {
+		set("secretKey", ds.S3.SecretKey)
+		set("sessionToken", ds.S3.SessionToken)
+	}
+	if ds.WebDAV != nil {
+		set("password", ds.WebDAV.Password)
+	}
+	if ds.SFTP != nil {
+		set("password", ds.SFTP.Password)
+	}
+	if ds.Rclone != nil {
+		for key, value := range ds.Rclone.Options {
+			if isSecretOption(key) {
+				set("options."+key, value)
+			}
+		}
+	}
+
+	return secrets
+}
+
+// Masked 返回去掉密钥的数据源副本
+func (ds DataSource) Masked() MaskedDataSource {
+	masked := MaskedDataSource{
+		DataSource:    ds,
+		MaskedSecrets: make(map[string]string),
+	}
+	for field, value := range ds.Secrets() {
+		masked.MaskedSecrets[field] = MaskSecret(field, value)
+	}
+
+	// 复制连接参数，避免修改到原配置
+	if ds.MountOptions != nil {
+		options := *ds.MountOptions
+		masked.MountOptions = &options
+	}
+	if ds.S3 != nil {
+		s3 := *ds.S3
+		s3.SecretKey = ""
+		s3.SessionToken = ""
+		masked.S3 = &s3
+	}
+	if ds.WebDAV != nil {
+		webdav := *ds.WebDAV
+		webdav.Password = ""
+		masked.WebDAV = &webdav
+	}
+	if ds.SFTP != nil {
+		sftp := *ds.SFTP
+		sftp.Password = ""
+		masked.SFTP = &sftp
+	}
+	if ds.Rclone != nil {
+		rclone := RcloneConfig{Backend: ds.Rclone.Backend, Options: make(map[string]string)}
+		for key, value := range ds.Rclone.Options {
+			if !isSecretOption(key) {
+				rclone.Options[key] = value
+			}
+		}
+		masked.Rclone = &rclone
+	}
+
+	return masked
+}
+
+// keepSecrets 更新数据源时，空的密钥字段沿用原有的值。
+// 前端拿不到明文密钥，未修改的密钥会以空值提交；rclone选项只沿用以空值提交的选项，
+// 未提交的选项视为已删除。
+func keepSecrets(existing DataSource, ds *DataSource) {
+	if existing.Type != ds.Type {
+		return
+	}
+
+	if ds.S3 != nil && existing.S3 != nil {
+		if ds.S3.SecretKey == "" {
+			ds.S3.SecretKey = existing.S3.SecretKey
+		}
+		if ds.S3.SessionToken == "" && ds.S3.CredentialMode == CredentialSession {
+			ds.S3.SessionToken = existing.S3.SessionToken
+		}
+	}
+	if ds.WebDAV != nil && existing.WebDAV != nil && ds.WebDAV.Password == "" {
+		ds.WebDAV.Password = existing.WebDAV.Password
+	}
+	if ds.SFTP != nil && existing.SFTP != nil && ds.SFTP.Password == "" {
+		ds.SFTP.Password = existing.SFTP.Password
+	}
+	if ds.Rclone != nil && existing.Rclone != nil {
+		for key, value := range ds.Rclone.Options {
+			if value != "" || !isSecretOption(key) {
+				continue
+			}
+			if previous, ok := existing.Rclone.Options[key]; ok {
+				ds.Rclone.Options[key] = previous
+			}
+		}
+	}
+}
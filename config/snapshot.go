@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxSnapshots 保留的加密快照数量
+	MaxSnapshots = 10
+
+	snapshotPrefix     = "config-"
+	snapshotSuffix     = ".enc"
+	snapshotTimeLayout = "20060102-150405.000000000"
+)
+
+// Snapshot 配置快照信息
+type Snapshot struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Size      int64     `json:"size"`
+}
+
+// snapshotCurrent 将当前磁盘上的配置文件保存为快照，并清理超出数量的旧快照
+func (cm *ConfigManager) snapshotCurrent() error {
+	data, err := ioutil.ReadFile(cm.configFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %v", err)
+	}
+
+	// 旧格式文件使用可轻易破解的XOR密钥加密，迁移时不保留，同时删除之前留下的旧格式快照
+	if !hasFileHeader(data) {
+		return cm.removeLegacySnapshots()
+	}
+
+	if err := os.MkdirAll(cm.backupDir, 0700); err != nil {
+		return fmt.Errorf("创建备份目录失败: %v", err)
+	}
+
+	name := snapshotPrefix + time.Now().Format(snapshotTimeLayout) + snapshotSuffix
+	if err := writeFileAtomic(filepath.Join(cm.backupDir, name), data, 0600); err != nil {
+		return fmt.Errorf("保存配置快照失败: %v", err)
+	}
+
+	return cm.pruneSnapshots()
+}
+
+// removeLegacySnapshots 删除没有文件头的旧格式快照
+func (cm *ConfigManager) removeLegacySnapshots() error {
+	snapshots, err := cm.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range snapshots {
+		path := filepath.Join(cm.backupDir, snapshot.ID)
+		data, err := ioutil.ReadFile(path)
+		if err != nil || hasFileHeader(data) {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除旧格式快照失败: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// pruneSnapshots 只保留最新的 MaxSnapshots 个快照
+func (cm *ConfigManager) pruneSnapshots() error {
+	snapshots, err := cm.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	for i := MaxSnapshots; i < len(snapshots); i++ {
+		if err := os.Remove(filepath.Join(cm.backupDir, snapshots[i].ID)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除旧快照失败: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// ListSnapshots 列出配置快照，最新的在前
+func (cm *ConfigManager) ListSnapshots() ([]Snapshot, error) {
+	entries, err := ioutil.ReadDir(cm.backupDir)
+	if os.IsNotExist(err) {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取备份目录失败: %v", err)
+	}
+
+	snapshots := []Snapshot{}
+	for _, entry := range entries {
+		createdAt, ok := parseSnapshotName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{
+			ID:        entry.Name(),
+			CreatedAt: createdAt,
+			Size:      entry.Size(),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+
+	return snapshots, nil
+}
+
+// RestoreSnapshot 校验快照可以用当前密钥解密后，用它替换当前配置文件。
+// 被替换的配置会先保存为新的快照，恢复操作本身也可以撤销。
+func (cm *ConfigManager) RestoreSnapshot(id string) (*AppConfig, error) {
+	if cm.gcm == nil {
+		return nil, fmt.Errorf("加密未初始化，无法解密配置")
+	}
+
+	if _, ok := parseSnapshotName(id); !ok || filepath.Base(id) != id {
+		return nil, fmt.Errorf("无效的快照: %s", id)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(cm.backupDir, id))
+	if err != nil {
+		return nil, fmt.Errorf("读取快照失败: %v", err)
+	}
+
+	plaintext, err := cm.decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("快照无法使用当前主密码解密: %w", err)
+	}
+
+	config, err := ParseConfig(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cm.withFileLock(func() error {
+		if err := cm.snapshotCurrent(); err != nil {
+			return err
+		}
+
+		if err := writeFileAtomic(cm.configFile, data, 0600); err != nil {
+			return fmt.Errorf("写入配置文件失败: %v", err)
+		}
+
+		cm.recordDiskState()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// parseSnapshotName 从快照文件名解析创建时间
+func parseSnapshotName(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, snapshotPrefix) || !strings.HasSuffix(name, snapshotSuffix) {
+		return time.Time{}, false
+	}
+
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, snapshotPrefix), snapshotSuffix)
+	t, err := time.ParseInLocation(snapshotTimeLayout, stamp, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeLegacyConfig 按引入文件头之前的格式写入配置: nonce | ciphertext，密钥为XOR派生，没有附加认证数据
+func writeLegacyConfig(t *testing.T, path, password string, config *AppConfig) []byte {
+	t.Helper()
+
+	plaintext, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	gcm, err := newGCM(legacyDeriveKey(password))
+	if err != nil {
+		t.Fatalf("newGCM: %v", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		t.Fatalf("nonce: %v", err)
+	}
+
+	data := gcm.Seal(nonce, nonce, plaintext, nil)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return data
+}
+
+func TestMigrationDoesNotSnapshotLegacyFile(t *testing.T) {
+	dir := t.TempDir()
+	writeLegacyConfig(t, filepath.Join(dir, "config.enc"), "password", &AppConfig{DataSources: []DataSource{}})
+
+	// 旧版本留下的旧格式快照
+	backupDir := filepath.Join(dir, "backups")
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	oldSnapshot := filepath.Join(backupDir, "config-20240101-000000.000000000.enc")
+	writeLegacyConfig(t, oldSnapshot, "password", &AppConfig{DataSources: []DataSource{}})
+
+	cm, err := NewConfigManager(dir, "password")
+	if err != nil {
+		t.Fatalf("NewConfigManager: %v", err)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if err := cm.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+
+	snapshots, err := cm.ListSnapshots()
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Fatalf("legacy snapshots kept after migration: %+v", snapshots)
+	}
+	if _, err := os.Stat(oldSnapshot); !os.IsNotExist(err) {
+		t.Fatalf("old legacy snapshot was not removed: %v", err)
+	}
+
+	// 迁移后的保存照常保留快照
+	if err := cm.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	snapshots, err = cm.ListSnapshots()
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("len(snapshots) = %d, want 1", len(snapshots))
+	}
+	data, err := os.ReadFile(filepath.Join(backupDir, snapshots[0].ID))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !hasFileHeader(data) {
+		t.Error("snapshot is not in the current format")
+	}
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// 校验错误码，前端根据错误码定位到具体表单字段
+const (
+	CodeRequired      = "required"
+	CodeTooLong       = "too_long"
+	CodeInvalidChars  = "invalid_chars"
+	CodeInvalidFormat = "invalid_format"
+	CodeInvalidURL    = "invalid_url"
+	CodeInvalidScheme = "invalid_scheme"
+	CodeInvalidValue  = "invalid_value"
+)
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError 数据源校验失败，包含所有字段的错误
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// Error 实现error接口
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Message)
+	}
+	return "数据源配置无效: " + strings.Join(messages, "; ")
+}
+
+// add 添加字段错误
+func (e *ValidationError) add(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+var (
+	// rclone remote名称规则: 字母、数字、_ . + @ - 和空格，不能以 - 或空格开头，不能以空格结尾
+	remoteNamePattern = regexp.MustCompile(`^[\p{L}\p{N}_.+@ -]+$`)
+	regionPattern     = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	bucketPattern     = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]*[a-z0-9]$`)
+	profilePattern    = regexp.MustCompile(`^[A-Za-z0-9_.+@-]+$`)
+	// 兼容MinIO等服务的STS，分区部分不限定为aws
+	roleARNPattern     = regexp.MustCompile(`^arn:[a-z0-9-]+:iam::[0-9]*:role/[\w+=,.@/-]+$`)
+	sessionNamePattern = regexp.MustCompile(`^[\w+=,.@-]{2,64}$`)
+)
+
+const (
+	maxNameLength   = 64
+	maxRegionLength = 64
+)
+
+// validateS3 校验S3连接参数
+func validateS3(verr *ValidationError, c S3Config) {
+	validateCredentials(verr, c)
+
+	provider, ok := LookupProvider(c.Provider)
+	if !ok {
+		verr.add("provider", CodeInvalidValue, "不支持的服务商: "+c.Provider)
+	}
+
+	if c.Endpoint != "" {
+		validateURL(verr, "endpoint", "Endpoint", c.Endpoint)
+	} else if ok && provider.RequiresEndpoint {
+		verr.add("endpoint", CodeRequired, provider.Name+" 必须填写 Endpoint")
+	}
+
+	validateRegion(verr, c.Region)
+
+	if c.Bucket != "" {
+		validateBucket(verr, c.Bucket)
+	}
+}
+
+// validateCredentials 按凭证方式校验S3凭证
+func validateCredentials(verr *ValidationError, c S3Config) {
+	switch c.CredentialMode {
+	case "", CredentialStatic, CredentialSession, CredentialAssumeRole:
+		if strings.TrimSpace(c.AccessKey) == "" {
+			verr.add("accessKey", CodeRequired, "Access Key 不能为空")
+		} else if hasControlChars(c.AccessKey) {
+			verr.add("accessKey", CodeInvalidChars, "Access Key 不能包含换行等控制字符")
+		}
+
+		if strings.TrimSpace(c.SecretKey) == "" {
+			verr.add("secretKey", CodeRequired, "Secret Key 不能为空")
+		} else if hasControlChars(c.SecretKey) {
+			verr.add("secretKey", CodeInvalidChars, "Secret Key 不能包含换行等控制字符")
+		}
+	case CredentialProfile:
+		switch {
+		case c.Profile == "":
+			verr.add("profile", CodeRequired, "配置名不能为空")
+		case !profilePattern.MatchString(c.Profile):
+			verr.add("profile", CodeInvalidChars, "配置名只能包含字母、数字和 _ . + @ -")
+		}
+	default:
+		verr.add("credentialMode", CodeInvalidValue, "不支持的凭证方式: "+c.CredentialMode)
+	}
+
+	if c.CredentialMode == CredentialSession {
+		if strings.TrimSpace(c.SessionToken) == "" {
+			verr.add("sessionToken", CodeRequired, "Session Token 不能为空")
+		} else if hasControlChars(c.SessionToken) {
+			verr.add("sessionToken", CodeInvalidChars, "Session Token 不能包含换行等控制字符")
+		}
+	}
+
+	if c.CredentialMode == CredentialAssumeRole {
+		switch {
+		case c.RoleARN == "":
+			verr.add("roleArn", CodeRequired, "角色ARN不能为空")
+		case !roleARNPattern.MatchString(c.RoleARN):
+			verr.add("roleArn", CodeInvalidFormat, "角色ARN格式无效，例如 arn:aws:iam::123456789012:role/name")
+		}
+
+		if hasControlChars(c.ExternalID) {
+			verr.add("externalId", CodeInvalidChars, "External ID 不能包含换行等控制字符")
+		}
+
+		if c.RoleSessionName != "" && !sessionNamePattern.MatchString(c.RoleSessionName) {
+			verr.add("roleSessionName", CodeInvalidChars, "会话名称只能包含字母、数字和 =,.@-，长度2到64个字符")
+		}
+
+		if c.STSEndpoint != "" {
+			validateURL(verr, "stsEndpoint", "STS Endpoint", c.STSEndpoint)
+		}
+	}
+}
+
+// validateName 按rclone remote名称规则校验数据源名称
+func validateName(verr *ValidationError, name string) {
+	switch {
+	case name == "":
+		verr.add("name", CodeRequired, "名称不能为空")
+	case len([]rune(name)) > maxNameLength:
+		verr.add("name", CodeTooLong, "名称不能超过64个字符")
+	case !remoteNamePattern.MatchString(name):
+		verr.add("name", CodeInvalidChars, "名称只能包含字母、数字、空格和 _ . + @ -")
+	case strings.HasPrefix(name, "-") || strings.HasPrefix(name, " ") || strings.HasSuffix(name, " "):
+		verr.add("name", CodeInvalidFormat, "名称不能以 - 或空格开头，也不能以空格结尾")
+	case strings.Trim(name, ".") == "":
+		// 名称用作默认挂载目录名，. 和 .. 会指向挂载根目录本身或其上级目录
+		verr.add("name", CodeInvalidFormat, "名称不能只包含 .")
+	}
+}
+
+// validateURL 校验http(s) URL
+func validateURL(verr *ValidationError, field, label, rawURL string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		verr.add(field, CodeInvalidURL, label+" 不是有效的URL")
+		return
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		verr.add(field, CodeInvalidScheme, label+" 必须以 http:// 或 https:// 开头")
+		return
+	}
+
+	if u.Hostname() == "" {
+		verr.add(field, CodeInvalidURL, label+" 缺少主机名")
+		return
+	}
+
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		verr.add(field, CodeInvalidURL, label+" 不能包含用户信息、查询参数或锚点")
+	}
+}
+
+// validateRegion 校验区域格式
+func validateRegion(verr *ValidationError, region string) {
+	switch {
+	case region == "":
+		verr.add("region", CodeRequired, "区域不能为空")
+	case len(region) > maxRegionLength:
+		verr.add("region", CodeTooLong, "区域不能超过64个字符")
+	case !regionPattern.MatchString(region):
+		verr.add("region", CodeInvalidFormat, "区域只能包含小写字母、数字和连字符，例如 us-east-1")
+	}
+}
+
+// validateBucket 按S3存储桶命名规则校验
+func validateBucket(verr *ValidationError, bucket string) {
+	switch {
+	case len(bucket) < 3 || len(bucket) > 63:
+		verr.add("bucket", CodeInvalidFormat, "存储桶名称长度必须在3到63个字符之间")
+	case !bucketPattern.MatchString(bucket):
+		verr.add("bucket", CodeInvalidChars, "存储桶名称只能包含小写字母、数字、点和连字符，且必须以字母或数字开头和结尾")
+	case strings.Contains(bucket, ".."):
+		verr.add("bucket", CodeInvalidFormat, "存储桶名称不能包含连续的点")
+	case net.ParseIP(bucket) != nil:
+		verr.add("bucket", CodeInvalidFormat, "存储桶名称不能是IP地址格式")
+	case strings.HasPrefix(bucket, "xn--") || strings.HasPrefix(bucket, "sthree-"):
+		verr.add("bucket", CodeInvalidFormat, "存储桶名称不能以 xn-- 或 sthree- 开头")
+	case strings.HasSuffix(bucket, "-s3alias") || strings.HasSuffix(bucket, "--ol-s3"):
+		verr.add("bucket", CodeInvalidFormat, "存储桶名称不能以 -s3alias 或 --ol-s3 结尾")
+	}
+}
+
+// hasControlChars 检查是否包含控制字符，这些字符写入rclone.conf会破坏INI格式
+func hasControlChars(s string) bool {
+	return strings.IndexFunc(s, unicode.IsControl) >= 0
+}
@@ -18,7 +18,8 @@ import Settings from './components/Settings';
 import SetPassword from './components/SetPassword';
 
 // Wails API
-import { SetMasterPassword, GetS3DataSources, IsAutoStartEnabled } from '../wailsjs/go/main/App';
+import { SetMasterPassword, GetDataSources, IsAutoStartEnabled, Lock as LockConfig, ReportActivity } from '../wailsjs/go/main/App';
+import { EventsOn } from '../wailsjs/runtime/runtime';
 
 // Icons
 import { Cloud, FolderOpen, Settings as SettingsIcon, Home, Lock, Server } from 'lucide-react';
@@ -90,10 +91,35 @@ function App() {
     initializeApp();
   }, []);
 
+  // 配置被锁定（手动或空闲超时）时回到密码界面
+  useEffect(() => {
+    return EventsOn('config:locked', () => {
+      setMasterPassword('');
+      setError('');
+      setNeedsPassword(true);
+    });
+  }, []);
+
+  // 上报用户操作以推迟空闲自动锁定，每30秒最多上报一次
+  useEffect(() => {
+    let last = 0;
+    const onActivity = () => {
+      const now = Date.now();
+      if (now - last > 30000) {
+        last = now;
+        ReportActivity();
+      }
+    };
+
+    const events = ['mousedown', 'keydown', 'wheel', 'touchstart'];
+    events.forEach((name) => window.addEventListener(name, onActivity, { passive: true }));
+    return () => events.forEach((name) => window.removeEventListener(name, onActivity));
+  }, []);
+
   const initializeApp = async () => {
     try {
       // 尝试获取数据源列表来检查是否需要密码
-      await GetS3DataSources();
+      await GetDataSources();
       setIsInitialized(true);
     } catch (err) {
       if (err.message && err.message.includes('配置未初始化')) {
@@ -110,6 +136,7 @@ function App() {
     try {
       await SetMasterPassword(masterPassword);
       setNeedsPassword(false);
+      setMasterPassword('');
     } catch (err) {
       setError('设置主密码失败: ' + err.message);
     }
@@ -176,6 +203,9 @@ function App() {
             </div>
             <div className="flex items-center space-x-4" style={{ WebkitAppRegion: 'no-drag' }}>
               <Navigation />
+              <Button variant="ghost" size="sm" onClick={() => LockConfig()} title="锁定">
+                <Lock className="h-4 w-4" />
+              </Button>
             </div>
           </div>
         </header>
@@ -186,7 +216,7 @@ function App() {
             <Route exact path="/" component={Dashboard} />
             <Route exact path="/data-sources" component={DataSourceList} />
             <Route path="/data-sources/add" component={DataSourceForm} />
-            <Route path="/data-sources/:name/browse" component={FileBrowser} />
+            <Route path="/data-sources/:id/browse" component={FileBrowser} />
             <Route path="/mounts" component={MountManager} />
             <Route path="/settings" component={Settings} />
             <Route path="/set-password" component={SetPassword} />
@@ -1,6 +1,7 @@
 import React, { useState, useEffect } from 'react';
 import { Link } from 'react-router-dom';
-import { GetS3DataSources, GetMounts } from '../../wailsjs/go/main/App';
+import { GetDataSources, GetMounts } from '../../wailsjs/go/main/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
 
 // shadcn/ui components
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card';
@@ -19,11 +20,16 @@ function Dashboard() {
     loadDashboardData();
   }, []);
 
+  // 配置文件被其他实例修改后重新加载
+  useEffect(() => {
+    return EventsOn('config:reloaded', loadDashboardData);
+  }, []);
+
   const loadDashboardData = async () => {
     try {
       setLoading(true);
       const [sources, mountList] = await Promise.all([
-        GetS3DataSources(),
+        GetDataSources(),
         GetMounts()
       ]);
 
@@ -134,10 +140,10 @@ function Dashboard() {
                   <div key={source.id} className="flex items-center justify-between p-3 border rounded-lg">
                     <div>
                       <h3 className="font-medium">{source.name}</h3>
-                      <p className="text-sm text-muted-foreground">{source.endpoint || source.region}</p>
+                      <p className="text-sm text-muted-foreground">{source.s3?.endpoint || source.s3?.region || source.webdav?.url || source.sftp?.host || source.rclone?.backend}</p>
                     </div>
                     <Button variant="ghost" size="sm" asChild>
-                      <Link to={`/data-sources/${source.name}/browse`}>
+                      <Link to={`/data-sources/${source.id}/browse`}>
                         <Folder className="h-4 w-4 mr-1" />
                         浏览
                       </Link>
@@ -179,7 +185,7 @@ function Dashboard() {
             ) : (
               <div className="space-y-4">
                 {mounts.map((mount) => (
-                  <div key={mount.name} className="flex items-center justify-between p-3 border rounded-lg">
+                  <div key={mount.id} className="flex items-center justify-between p-3 border rounded-lg">
                     <div>
                       <h3 className="font-medium">{mount.name}</h3>
                       <p className="text-sm text-muted-foreground">{mount.localPath}</p>
@@ -1,6 +1,6 @@
-import React, { useState } from 'react';
+import React, { useState, useEffect } from 'react';
 import { useHistory } from 'react-router-dom';
-import { AddS3DataSource, TestS3Connection } from '../../wailsjs/go/main/App';
+import { AddDataSource, TestDataSource, GetS3Providers } from '../../wailsjs/go/main/App';
 
 // shadcn/ui components
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card';
@@ -39,21 +39,44 @@ const awsRegions = [
   { value: 'af-south-1', label: 'Africa (Cape Town)' }
 ];
 
+// 凭证方式
+const credentialModes = [
+  { value: 'static', label: 'Access Key / Secret Key' },
+  { value: 'session', label: '临时凭证（Session Token）' },
+  { value: 'profile', label: 'AWS 配置文件（~/.aws/config）' },
+  { value: 'assume-role', label: 'STS AssumeRole' }
+];
+
 function DataSourceForm() {
   const history = useHistory();
   const [formData, setFormData] = useState({
     name: '',
+    provider: 'aws',
     endpoint: '',
     accessKey: '',
     secretKey: '',
     region: 'us-east-1',
     bucket: '',
-    description: ''
+    description: '',
+    credentialMode: 'static',
+    sessionToken: '',
+    profile: '',
+    roleArn: '',
+    externalId: '',
+    roleSessionName: '',
+    stsEndpoint: ''
   });
   const [testing, setTesting] = useState(false);
   const [submitting, setSubmitting] = useState(false);
   const [error, setError] = useState('');
   const [success, setSuccess] = useState('');
+  const [providers, setProviders] = useState([]);
+
+  useEffect(() => {
+    GetS3Providers().then((list) => setProviders(list || []));
+  }, []);
+
+  const currentProvider = providers.find((p) => p.id === formData.provider);
 
   const handleInputChange = (e) => {
     const { name, value } = e.target;
@@ -63,6 +86,62 @@ function DataSourceForm() {
     }));
   };
 
+  const handleProviderChange = (value) => {
+    const provider = providers.find((p) => p.id === value);
+    setFormData(prev => ({
+      ...prev,
+      provider: value,
+      region: provider?.defaultRegion || prev.region
+    }));
+  };
+
+  const handleCredentialModeChange = (value) => {
+    setFormData(prev => ({
+      ...prev,
+      credentialMode: value
+    }));
+  };
+
+  const usesKeys = formData.credentialMode !== 'profile';
+
+  // 检查当前凭证方式的必填项
+  const hasRequiredFields = () => {
+    if (!formData.name || !formData.region) {
+      return false;
+    }
+    switch (formData.credentialMode) {
+      case 'profile':
+        return !!formData.profile;
+      case 'session':
+        return !!(formData.accessKey && formData.secretKey && formData.sessionToken);
+      case 'assume-role':
+        return !!(formData.accessKey && formData.secretKey && formData.roleArn);
+      default:
+        return !!(formData.accessKey && formData.secretKey);
+    }
+  };
+
+  const buildDataSource = () => ({
+    name: formData.name,
+    type: 's3',
+    description: formData.description,
+    s3: {
+      provider: formData.provider,
+      endpoint: formData.endpoint,
+      accessKey: usesKeys ? formData.accessKey : '',
+      secretKey: usesKeys ? formData.secretKey : '',
+      region: formData.region,
+      bucket: formData.bucket,
+      credentialMode: formData.credentialMode,
+      sessionToken: formData.credentialMode === 'session' ? formData.sessionToken : '',
+      profile: formData.credentialMode === 'profile' ? formData.profile : '',
+      roleArn: formData.credentialMode === 'assume-role' ? formData.roleArn : '',
+      externalId: formData.credentialMode === 'assume-role' ? formData.externalId : '',
+      roleSessionName: formData.credentialMode === 'assume-role' ? formData.roleSessionName : '',
+      stsEndpoint: formData.credentialMode === 'assume-role' ? formData.stsEndpoint : ''
+    }
+  });
+
   const handleRegionChange = (value) => {
     setFormData(prev => ({
       ...prev,
@@ -71,7 +150,7 @@ function DataSourceForm() {
   };
 
   const handleTestConnection = async () => {
-    if (!formData.name || !formData.accessKey || !formData.secretKey || !formData.region) {
+    if (!hasRequiredFields()) {
       setError('请填写必要的连接信息');
       return;
     }
@@ -81,14 +160,7 @@ function DataSourceForm() {
       setError('');
       setSuccess('');
 
-      const result = await TestS3Connection(
-        formData.name,
-        formData.endpoint,
-        formData.accessKey,
-        formData.secretKey,
-        formData.region,
-        formData.bucket
-      );
+      const result = await TestDataSource(buildDataSource());
 
       setSuccess('连接测试成功！');
     } catch (err) {
@@ -104,7 +176,7 @@ function DataSourceForm() {
   const handleSubmit = async (e) => {
     e.preventDefault();
 
-    if (!formData.name || !formData.accessKey || !formData.secretKey || !formData.region) {
+    if (!hasRequiredFields()) {
       setError('请填写所有必填字段');
       return;
     }
@@ -114,15 +186,7 @@ function DataSourceForm() {
       setError('');
       setSuccess('');
 
-      const result = await AddS3DataSource(
-        formData.name,
-        formData.endpoint,
-        formData.accessKey,
-        formData.secretKey,
-        formData.region,
-        formData.bucket,
-        formData.description
-      );
+      const result = await AddDataSource(buildDataSource());
 
       setSuccess('数据源添加成功！');
       setTimeout(() => {
@@ -193,43 +257,164 @@ function DataSourceForm() {
                 />
               </div>
 
-              {/* 访问密钥 */}
-              <div className="space-y-2">
-                <Label htmlFor="accessKey" className="flex items-center space-x-2">
-                  <Key className="h-4 w-4" />
-                  <span>
-                    Access Key <span className="text-destructive">*</span>
-                  </span>
+              {/* 服务商 */}
+              <div className="md:col-span-2 space-y-2">
+                <Label htmlFor="provider">
+                  服务商 <span className="text-destructive">*</span>
                 </Label>
-                <Input
-                  id="accessKey"
-                  name="accessKey"
-                  value={formData.accessKey}
-                  onChange={handleInputChange}
-                  placeholder="AWS Access Key ID"
+                <SelectCustom
+                  value={formData.provider}
+                  onValueChange={handleProviderChange}
+                  options={providers.map((p) => ({ value: p.id, label: p.name }))}
+                  placeholder="选择S3兼容服务商"
                   required
                 />
               </div>
 
-              {/* 秘密密钥 */}
-              <div className="space-y-2">
-                <Label htmlFor="secretKey" className="flex items-center space-x-2">
-                  <Key className="h-4 w-4" />
-                  <span>
-                    Secret Key <span className="text-destructive">*</span>
-                  </span>
+              {/* 凭证方式 */}
+              <div className="md:col-span-2 space-y-2">
+                <Label htmlFor="credentialMode">
+                  凭证方式 <span className="text-destructive">*</span>
                 </Label>
-                <Input
-                  id="secretKey"
-                  name="secretKey"
-                  type="password"
-                  value={formData.secretKey}
-                  onChange={handleInputChange}
-                  placeholder="AWS Secret Access Key"
+                <SelectCustom
+                  value={formData.credentialMode}
+                  onValueChange={handleCredentialModeChange}
+                  options={credentialModes}
+                  placeholder="选择凭证方式"
                   required
                 />
               </div>
 
+              {usesKeys && (
+                <>
+                  {/* 访问密钥 */}
+                  <div className="space-y-2">
+                    <Label htmlFor="accessKey" className="flex items-center space-x-2">
+                      <Key className="h-4 w-4" />
+                      <span>
+                        {formData.credentialMode === 'assume-role' ? '源 Access Key' : 'Access Key'} <span className="text-destructive">*</span>
+                      </span>
+                    </Label>
+                    <Input
+                      id="accessKey"
+                      name="accessKey"
+                      value={formData.accessKey}
+                      onChange={handleInputChange}
+                      placeholder="AWS Access Key ID"
+                      required={usesKeys}
+                    />
+                  </div>
+
+                  {/* 秘密密钥 */}
+                  <div className="space-y-2">
+                    <Label htmlFor="secretKey" className="flex items-center space-x-2">
+                      <Key className="h-4 w-4" />
+                      <span>
+                        {formData.credentialMode === 'assume-role' ? '源 Secret Key' : 'Secret Key'} <span className="text-destructive">*</span>
+                      </span>
+                    </Label>
+                    <Input
+                      id="secretKey"
+                      name="secretKey"
+                      type="password"
+                      value={formData.secretKey}
+                      onChange={handleInputChange}
+                      placeholder="AWS Secret Access Key"
+                      required={usesKeys}
+                    />
+                  </div>
+                </>
+              )}
+
+              {formData.credentialMode === 'session' && (
+                <div className="md:col-span-2 space-y-2">
+                  <Label htmlFor="sessionToken">
+                    Session Token <span className="text-destructive">*</span>
+                  </Label>
+                  <Input
+                    id="sessionToken"
+                    name="sessionToken"
+                    type="password"
+                    value={formData.sessionToken}
+                    onChange={handleInputChange}
+                    placeholder="AWS Session Token"
+                    required
+                  />
+                </div>
+              )}
+
+              {formData.credentialMode === 'profile' && (
+                <div className="md:col-span-2 space-y-2">
+                  <Label htmlFor="profile">
+                    配置名 <span className="text-destructive">*</span>
+                  </Label>
+                  <Input
+                    id="profile"
+                    name="profile"
+                    value={formData.profile}
+                    onChange={handleInputChange}
+                    placeholder="例如：default"
+                    required
+                  />
+                  <p className="text-xs text-muted-foreground">
+                    使用 ~/.aws/config 和 ~/.aws/credentials 中的同名配置
+                  </p>
+                </div>
+              )}
+
+              {formData.credentialMode === 'assume-role' && (
+                <>
+                  <div className="md:col-span-2 space-y-2">
+                    <Label htmlFor="roleArn">
+                      角色 ARN <span className="text-destructive">*</span>
+                    </Label>
+                    <Input
+                      id="roleArn"
+                      name="roleArn"
+                      value={formData.roleArn}
+                      onChange={handleInputChange}
+                      placeholder="arn:aws:iam::123456789012:role/name"
+                      required
+                    />
+                  </div>
+
+                  <div className="space-y-2">
+                    <Label htmlFor="externalId">External ID</Label>
+                    <Input
+                      id="externalId"
+                      name="externalId"
+                      value={formData.externalId}
+                      onChange={handleInputChange}
+                    />
+                  </div>
+
+                  <div className="space-y-2">
+                    <Label htmlFor="roleSessionName">会话名称</Label>
+                    <Input
+                      id="roleSessionName"
+                      name="roleSessionName"
+                      value={formData.roleSessionName}
+                      onChange={handleInputChange}
+                      placeholder="rmount"
+                    />
+                  </div>
+
+                  <div className="md:col-span-2 space-y-2">
+                    <Label htmlFor="stsEndpoint">STS 端点</Label>
+                    <Input
+                      id="stsEndpoint"
+                      name="stsEndpoint"
+                      value={formData.stsEndpoint}
+                      onChange={handleInputChange}
+                      placeholder="留空使用 AWS 默认地址"
+                    />
+                    <p className="text-xs text-muted-foreground">
+                      临时凭证会在过期前自动刷新
+                    </p>
+                  </div>
+                </>
+              )}
+
               {/* 区域 */}
               <div className="space-y-2">
                 <Label htmlFor="region">
@@ -256,9 +441,12 @@ function DataSourceForm() {
                   value={formData.endpoint}
                   onChange={handleInputChange}
                   placeholder="例如：https://s3.example.com"
+                  required={currentProvider?.requiresEndpoint}
                 />
                 <p className="text-xs text-muted-foreground">
-                  仅用于兼容S3的存储服务，如MinIO、腾讯云COS等。如果使用AWS请留空。
+                  {currentProvider?.requiresEndpoint
+                    ? '该服务商必须填写端点地址。'
+                    : '留空时根据服务商和区域自动生成端点。'}
                 </p>
               </div>
 
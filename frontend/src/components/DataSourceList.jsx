@@ -1,6 +1,7 @@
 import React, { useState, useEffect } from 'react';
 import { Link } from 'react-router-dom';
-import { GetS3DataSources } from '../../wailsjs/go/main/App';
+import { GetDataSources } from '../../wailsjs/go/main/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
 
 function DataSourceList() {
   const [dataSources, setDataSources] = useState([]);
@@ -11,10 +12,15 @@ function DataSourceList() {
     loadDataSources();
   }, []);
 
+  // 配置文件被其他实例修改后重新加载
+  useEffect(() => {
+    return EventsOn('config:reloaded', loadDataSources);
+  }, []);
+
   const loadDataSources = async () => {
     try {
       setLoading(true);
-      const sources = await GetS3DataSources();
+      const sources = await GetDataSources();
       setDataSources(sources || []);
     } catch (err) {
       setError('加载数据源失败: ' + err.message);
@@ -96,11 +102,11 @@ function DataSourceList() {
                   </td>
                   <td className="px-6 py-4 whitespace-nowrap">
                     <div className="text-sm text-gray-300">
-                      {source.endpoint || source.region}
+                      {source.s3?.endpoint || source.s3?.region || source.webdav?.url || source.sftp?.host || source.rclone?.backend}
                     </div>
                   </td>
                   <td className="px-6 py-4 whitespace-nowrap">
-                    <div className="text-sm text-gray-300">{source.bucket || '--'}</div>
+                    <div className="text-sm text-gray-300">{source.s3?.bucket || '--'}</div>
                   </td>
                   <td className="px-6 py-4">
                     <div className="text-sm text-gray-300">{source.description || '--'}</div>
@@ -108,7 +114,7 @@ function DataSourceList() {
                   <td className="px-6 py-4 whitespace-nowrap text-right text-sm font-medium">
                     <div className="flex justify-end space-x-2">
                       <Link
-                        to={`/data-sources/${source.name}/browse`}
+                        to={`/data-sources/${source.id}/browse`}
                         className="text-blue-400 hover:text-blue-300"
                       >
                         浏览
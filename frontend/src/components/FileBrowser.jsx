@@ -12,7 +12,7 @@ import { Badge } from '@/components/ui/badge';
 import { FolderOpen, File, ArrowLeft, Download, AlertCircle, HardDrive } from 'lucide-react';
 
 function FileBrowser() {
-  const { name } = useParams();
+  const { id } = useParams();
   const history = useHistory();
   const [files, setFiles] = useState([]);
   const [currentPath, setCurrentPath] = useState('');
@@ -21,13 +21,13 @@ function FileBrowser() {
 
   useEffect(() => {
     loadFiles(currentPath);
-  }, [name, currentPath]);
+  }, [id, currentPath]);
 
   const loadFiles = async (path) => {
     try {
       setLoading(true);
       setError('');
-      const fileList = await ListFiles(name, path);
+      const fileList = await ListFiles(id, path);
       setFiles(fileList || []);
     } catch (err) {
       setError('加载文件失败: ' + err.message);
@@ -84,7 +84,7 @@ function FileBrowser() {
       <div className="flex justify-between items-center">
         <div className="flex items-center space-x-4">
           <h1 className="text-3xl font-bold">文件浏览器</h1>
-          <Badge variant="secondary">{name}</Badge>
+          <Badge variant="secondary">{id}</Badge>
         </div>
         <div className="flex items-center space-x-2">
           <Button
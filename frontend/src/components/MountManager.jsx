@@ -1,6 +1,7 @@
 import React, { useState, useEffect } from 'react';
-import { GetMounts, Mount, Unmount } from '../../wailsjs/go/main/App';
-import { GetS3DataSources } from '../../wailsjs/go/main/App';
+import { GetMounts, Mount, Unmount, SetMountAtStartup, GetMountLog, WatchMountLog, UnwatchMountLog } from '../../wailsjs/go/main/App';
+import { GetDataSources } from '../../wailsjs/go/main/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
 
 // shadcn/ui components
 import { Button } from '@/components/ui/button';
@@ -9,10 +10,33 @@ import { Label } from '@/components/ui/label';
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card';
 import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@/components/ui/select';
 import { Badge } from '@/components/ui/badge';
+import { Switch } from '@/components/ui/switch';
 import { Table, TableBody, TableCell, TableHead, TableHeader, TableRow } from '@/components/ui/table';
 
 // Icons
-import { FolderOpen, ExternalLink, Trash2, Server, HardDrive, AlertCircle } from 'lucide-react';
+import { FolderOpen, ExternalLink, Trash2, Server, HardDrive, AlertCircle, FileText, X } from 'lucide-react';
+
+// 日志面板最多保留的行数
+const MAX_LOG_LINES = 1000;
+
+const STATUS_LABELS = {
+  mounted: '已挂载',
+  stale: '连接已断开',
+  restarting: '正在重新挂载',
+  failed: '重新挂载失败',
+};
+
+const statusLabel = (status) => STATUS_LABELS[status] || status;
+
+const statusVariant = (status) => {
+  if (status === 'mounted') {
+    return 'default';
+  }
+  if (status === 'stale' || status === 'failed') {
+    return 'destructive';
+  }
+  return 'secondary';
+};
 
 function MountManager() {
   const [mounts, setMounts] = useState([]);
@@ -21,17 +45,69 @@ function MountManager() {
   const [error, setError] = useState('');
   const [selectedSource, setSelectedSource] = useState('');
   const [remotePath, setRemotePath] = useState('');
+  const [logMount, setLogMount] = useState(null);
+  const [logLines, setLogLines] = useState([]);
 
   useEffect(() => {
     loadData();
   }, []);
 
+  // 配置文件被其他实例修改后重新加载
+  useEffect(() => {
+    return EventsOn('config:reloaded', loadData);
+  }, []);
+
+  // 挂载失效后自动重新挂载的状态变化
+  useEffect(() => {
+    return EventsOn('mount:status', loadData);
+  }, []);
+
+  // 自动挂载任务完成或失败后刷新列表
+  useEffect(() => {
+    return EventsOn('mount:job', (job) => {
+      if (job.status === 'mounted') {
+        loadData();
+      } else if (job.status === 'failed') {
+        setError('自动挂载失败: ' + job.error);
+      }
+    });
+  }, []);
+
+  // 打开日志面板时读取最近的日志，并实时接收新增的行
+  useEffect(() => {
+    if (!logMount) {
+      return;
+    }
+
+    let cancelled = false;
+    GetMountLog(logMount.id, 200)
+      .then((lines) => {
+        if (!cancelled) {
+          setLogLines(lines || []);
+        }
+      })
+      .catch((err) => setError('读取日志失败: ' + err.message));
+
+    WatchMountLog(logMount.id).catch((err) => setError('读取日志失败: ' + err.message));
+    const off = EventsOn('mount:log', (event) => {
+      if (event.mountId === logMount.id) {
+        setLogLines((prev) => [...prev, ...event.lines].slice(-MAX_LOG_LINES));
+      }
+    });
+
+    return () => {
+      cancelled = true;
+      off();
+      UnwatchMountLog(logMount.id);
+    };
+  }, [logMount]);
+
   const loadData = async () => {
     try {
       setLoading(true);
       const [mountList, sourceList] = await Promise.all([
         GetMounts(),
-        GetS3DataSources()
+        GetDataSources()
       ]);
 
       setMounts(mountList || []);
@@ -58,10 +134,20 @@ function MountManager() {
     }
   };
 
-  const handleUnmount = async (mountName) => {
+  const handleAutoMountChange = async (mountId, enabled) => {
     try {
       setError('');
-      await Unmount(mountName);
+      await SetMountAtStartup(mountId, enabled);
+      await loadData();
+    } catch (err) {
+      setError('设置自动挂载失败: ' + err.message);
+    }
+  };
+
+  const handleUnmount = async (mountId) => {
+    try {
+      setError('');
+      await Unmount(mountId);
       await loadData(); // 重新加载挂载列表
     } catch (err) {
       setError('卸载失败: ' + err.message);
@@ -114,7 +200,7 @@ function MountManager() {
                 </SelectTrigger>
                 <SelectContent>
                   {dataSources.map((source) => (
-                    <SelectItem key={source.id} value={source.name}>
+                    <SelectItem key={source.id} value={source.id}>
                       <div className="flex items-center space-x-2">
                         <Server className="h-4 w-4" />
                         <span>{source.name}</span>
@@ -176,12 +262,13 @@ function MountManager() {
                   <TableHead>远程路径</TableHead>
                   <TableHead>本地路径</TableHead>
                   <TableHead>状态</TableHead>
+                  <TableHead>自动挂载</TableHead>
                   <TableHead className="text-right">操作</TableHead>
                 </TableRow>
               </TableHeader>
               <TableBody>
                 {mounts.map((mount) => (
-                  <TableRow key={mount.name}>
+                  <TableRow key={mount.id}>
                     <TableCell>
                       <div className="flex items-center space-x-2">
                         <Server className="h-4 w-4 text-muted-foreground" />
@@ -190,26 +277,42 @@ function MountManager() {
                     </TableCell>
                     <TableCell>
                       <code className="bg-muted px-2 py-1 rounded text-sm">
-                        {mount.Remote || '/'}
+                        {mount.remote || '/'}
                       </code>
                     </TableCell>
                     <TableCell>
                       <div className="flex items-center space-x-2">
                         <FolderOpen className="h-4 w-4 text-muted-foreground" />
-                        <span className="text-sm">{mount.LocalPath}</span>
+                        <span className="text-sm">{mount.localPath}</span>
                       </div>
                     </TableCell>
                     <TableCell>
-                      <Badge variant={mount.Status === 'mounted' ? 'default' : 'secondary'}>
-                        {mount.Status === 'mounted' ? '已挂载' : mount.Status}
-                      </Badge>
+                      <div className="space-y-1">
+                        <Badge variant={statusVariant(mount.status)}>
+                          {statusLabel(mount.status)}
+                        </Badge>
+                        {mount.restarts > 0 && (
+                          <div className="text-xs text-muted-foreground" title={mount.lastError}>
+                            已重新挂载 {mount.restarts} 次
+                          </div>
+                        )}
+                        {mount.status === 'failed' && mount.lastError && (
+                          <div className="text-xs text-destructive break-all">{mount.lastError}</div>
+                        )}
+                      </div>
+                    </TableCell>
+                    <TableCell>
+                      <Switch
+                        checked={mount.autoMount}
+                        onCheckedChange={(enabled) => handleAutoMountChange(mount.id, enabled)}
+                      />
                     </TableCell>
                     <TableCell className="text-right">
                       <div className="flex justify-end space-x-2">
                         <Button
                           variant="ghost"
                           size="sm"
-                          onClick={() => window.open(`file://${mount.LocalPath}`)}
+                          onClick={() => window.open(`file://${mount.localPath}`)}
                           className="text-muted-foreground hover:text-foreground"
                         >
                           <ExternalLink className="h-4 w-4" />
@@ -218,7 +321,16 @@ function MountManager() {
                         <Button
                           variant="ghost"
                           size="sm"
-                          onClick={() => handleUnmount(mount.name)}
+                          onClick={() => { setLogLines([]); setLogMount(mount); }}
+                          className="text-muted-foreground hover:text-foreground"
+                        >
+                          <FileText className="h-4 w-4" />
+                          <span className="sr-only">日志</span>
+                        </Button>
+                        <Button
+                          variant="ghost"
+                          size="sm"
+                          onClick={() => handleUnmount(mount.id)}
                           className="text-muted-foreground hover:text-destructive"
                         >
                           <Trash2 className="h-4 w-4" />
@@ -234,6 +346,30 @@ function MountManager() {
         </CardContent>
       </Card>
 
+      {/* 挂载日志 */}
+      {logMount && (
+        <Card>
+          <CardHeader>
+            <CardTitle className="flex items-center justify-between">
+              <span className="flex items-center space-x-2">
+                <FileText className="h-5 w-5" />
+                <span>挂载日志 - {logMount.name}</span>
+              </span>
+              <Button variant="ghost" size="sm" onClick={() => setLogMount(null)}>
+                <X className="h-4 w-4" />
+                <span className="sr-only">关闭</span>
+              </Button>
+            </CardTitle>
+            <CardDescription>{logMount.localPath}</CardDescription>
+          </CardHeader>
+          <CardContent>
+            <pre className="bg-muted rounded p-3 text-xs max-h-96 overflow-auto whitespace-pre-wrap">
+              {logLines.length === 0 ? '暂无日志' : logLines.join('\n')}
+            </pre>
+          </CardContent>
+        </Card>
+      )}
+
       {/* 使用说明 */}
       <Card>
         <CardHeader>
@@ -1,5 +1,5 @@
 import React, { useState, useEffect } from 'react';
-import { SetGistConfig, GetGistConfig, SyncToGist, SetAutoStart, IsAutoStartEnabled, IsPasswordSet } from '../../wailsjs/go/main/App';
+import { SetGistConfig, GetGistConfig, SyncToGist, SetAutoStart, IsAutoStartEnabled, IsPasswordSet, GetAutoLock, SetAutoLock, GetRcloneLogLevel, SetRcloneLogLevel, GetMountDirectory, SetMountDirectory, RemountToMountDirectory } from '../../wailsjs/go/main/App';
 import { Link } from 'react-router-dom';
 
 // shadcn/ui components
@@ -9,6 +9,7 @@ import { Input } from '@/components/ui/input';
 import { Label } from '@/components/ui/label';
 import { Tabs, TabsContent, TabsList, TabsTrigger } from '@/components/ui/tabs';
 import { Switch } from '@/components/ui/switch';
+import { SelectCustom } from '@/components/ui/select-custom';
 
 // Icons
 import { Cloud, Settings as SettingsIcon, Github, Info, CheckCircle, AlertCircle, Lock } from 'lucide-react';
@@ -23,6 +24,9 @@ function Settings() {
   const [isEditingToken, setIsEditingToken] = useState(false);
   const [autoStartEnabled, setAutoStartEnabled] = useState(false);
   const [passwordSet, setPasswordSet] = useState(false);
+  const [autoLock, setAutoLock] = useState({ minutes: 0, mountPolicy: 'keep' });
+  const [rcloneLogLevel, setRcloneLogLevel] = useState('NOTICE');
+  const [mountDirectory, setMountDirectory] = useState('');
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState('');
   const [success, setSuccess] = useState('');
@@ -42,6 +46,13 @@ function Settings() {
       setAutoStartEnabled(enabled);
       setPasswordSet(isPasswordSet);
 
+      if (isPasswordSet) {
+        const [minutes, mountPolicy] = await GetAutoLock();
+        setAutoLock({ minutes, mountPolicy });
+        setRcloneLogLevel(await GetRcloneLogLevel());
+        setMountDirectory(await GetMountDirectory());
+      }
+
       if (gistConfig) {
         const [apiToken, gistId, hasToken] = gistConfig;
         setGistForm(prev => ({
@@ -123,6 +134,65 @@ function Settings() {
     }
   };
 
+  const handleAutoLockSave = async () => {
+    setLoading(true);
+    setError('');
+    setSuccess('');
+
+    try {
+      await SetAutoLock(Number(autoLock.minutes) || 0, autoLock.mountPolicy);
+      setSuccess('自动锁定设置已更新！');
+    } catch (err) {
+      console.error('SetAutoLock error:', err);
+      const errorMessage = err?.message || err?.toString() || '未知错误';
+      setError('设置自动锁定失败: ' + errorMessage);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleRcloneLogLevelChange = async (level) => {
+    setLoading(true);
+    setError('');
+    setSuccess('');
+
+    try {
+      await SetRcloneLogLevel(level);
+      setRcloneLogLevel(level);
+      setSuccess('日志级别已更新！');
+    } catch (err) {
+      console.error('SetRcloneLogLevel error:', err);
+      const errorMessage = err?.message || err?.toString() || '未知错误';
+      setError('设置日志级别失败: ' + errorMessage);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleMountDirectorySave = async () => {
+    setLoading(true);
+    setError('');
+    setSuccess('');
+
+    try {
+      const mounts = await SetMountDirectory(mountDirectory);
+      setSuccess('挂载根目录已更新！');
+
+      // 旧根目录下仍有挂载时询问是否移动到新位置
+      if (mounts && mounts.length > 0 &&
+          window.confirm(`有 ${mounts.length} 个挂载仍位于旧目录，是否重新挂载到新目录？`)) {
+        await RemountToMountDirectory(mounts.map((m) => m.id));
+        setSuccess('挂载已移动到新目录！');
+      }
+    } catch (err) {
+      console.error('SetMountDirectory error:', err);
+      const errorMessage = err?.message || err?.toString() || '未知错误';
+      setError('设置挂载根目录失败: ' + errorMessage);
+    } finally {
+      setLoading(false);
+    }
+  };
+
   return (
     <div className="space-y-8">
       <div className="flex justify-between items-center">
@@ -312,6 +382,86 @@ function Settings() {
                   )}
                 </div>
 
+                {/* 挂载根目录 */}
+                {passwordSet && (
+                  <div className="space-y-3">
+                    <div className="space-y-0.5">
+                      <Label htmlFor="mountDirectory">挂载根目录</Label>
+                      <p className="text-sm text-muted-foreground">
+                        未指定挂载路径时，数据源挂载到该目录下的同名文件夹
+                      </p>
+                    </div>
+                    <div className="flex space-x-2">
+                      <Input
+                        id="mountDirectory"
+                        value={mountDirectory}
+                        onChange={(e) => setMountDirectory(e.target.value)}
+                        placeholder="~/mounts"
+                      />
+                      <Button size="sm" onClick={handleMountDirectorySave} disabled={loading}>
+                        保存
+                      </Button>
+                    </div>
+                  </div>
+                )}
+
+                {/* 自动锁定 */}
+                {passwordSet && (
+                  <div className="space-y-3">
+                    <div className="space-y-0.5">
+                      <Label htmlFor="autoLockMinutes">空闲自动锁定</Label>
+                      <p className="text-sm text-muted-foreground">
+                        空闲超过指定分钟数后清除内存中的配置，需要重新输入主密码。0 表示不自动锁定
+                      </p>
+                    </div>
+                    <div className="grid gap-4 md:grid-cols-2">
+                      <Input
+                        id="autoLockMinutes"
+                        type="number"
+                        min="0"
+                        max="1440"
+                        value={autoLock.minutes}
+                        onChange={(e) => setAutoLock(prev => ({ ...prev, minutes: e.target.value }))}
+                      />
+                      <SelectCustom
+                        value={autoLock.mountPolicy}
+                        onValueChange={(value) => setAutoLock(prev => ({ ...prev, mountPolicy: value }))}
+                        options={[
+                          { value: 'keep', label: '锁定时保持挂载' },
+                          { value: 'unmount', label: '锁定时卸载所有数据源' }
+                        ]}
+                      />
+                    </div>
+                    <div className="flex justify-end">
+                      <Button size="sm" onClick={handleAutoLockSave} disabled={loading}>
+                        保存
+                      </Button>
+                    </div>
+                  </div>
+                )}
+
+                {/* rclone日志级别 */}
+                {passwordSet && (
+                  <div className="space-y-3">
+                    <div className="space-y-0.5">
+                      <Label>rclone 日志级别</Label>
+                      <p className="text-sm text-muted-foreground">
+                        对所有挂载生效，修改后立即应用。排查问题时可临时改为 DEBUG
+                      </p>
+                    </div>
+                    <SelectCustom
+                      value={rcloneLogLevel}
+                      onValueChange={handleRcloneLogLevelChange}
+                      options={[
+                        { value: 'DEBUG', label: 'DEBUG' },
+                        { value: 'INFO', label: 'INFO' },
+                        { value: 'NOTICE', label: 'NOTICE（默认）' },
+                        { value: 'ERROR', label: 'ERROR' }
+                      ]}
+                    />
+                  </div>
+                )}
+
                 <div className="border-t pt-6">
                   <h3 className="text-lg font-medium mb-3">应用信息</h3>
                   <div className="space-y-2 text-sm text-muted-foreground">
@@ -1,23 +1,69 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
-import {rclone} from '../models';
 import {config} from '../models';
+import {rclone} from '../models';
+
+export function AddDataSource(arg1:config.DataSource):Promise<void>;
+
+export function AddS3DataSource(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string,arg7:string,arg8:string):Promise<void>;
+
+export function ChangeMasterPassword(arg1:string,arg2:string):Promise<void>;
 
-export function AddS3DataSource(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string,arg7:string):Promise<void>;
+export function ExportConfig(arg1:string,arg2:string):Promise<void>;
+
+export function GetAutoLock():Promise<number>;
+
+export function GetDataSources():Promise<Array<config.MaskedDataSource>>;
+
+export function GetDefaultMountOptions():Promise<config.MountOptions>;
 
 export function GetGistConfig():Promise<string>;
 
+export function GetMountDirectory():Promise<string>;
+
+export function GetMountEntries():Promise<Array<config.MountEntry>>;
+
+export function GetMountLog(arg1:string,arg2:number):Promise<Array<string>>;
+
 export function GetMounts():Promise<Array<rclone.MountInfo>>;
 
-export function GetS3DataSources():Promise<Array<config.S3Config>>;
+export function GetRcloneLogLevel():Promise<string>;
+
+export function GetS3Providers():Promise<Array<config.Provider>>;
+
+export function GetTransferStats():Promise<rclone.RCStats>;
+
+export function ImportConfig(arg1:string,arg2:string,arg3:string):Promise<config.ImportResult>;
 
 export function IsAutoStartEnabled():Promise<boolean>;
 
+export function IsPasswordRemembered():Promise<boolean>;
+
 export function IsPasswordSet():Promise<boolean>;
 
+export function ListConfigSnapshots():Promise<Array<config.Snapshot>>;
+
 export function ListFiles(arg1:string,arg2:string):Promise<Array<rclone.FileInfo>>;
 
-export function Mount(arg1:string,arg2:string):Promise<void>;
+export function Lock():Promise<void>;
+
+export function Mount(arg1:string,arg2:string):Promise<rclone.MountInfo>;
+
+export function MountAt(arg1:string,arg2:string,arg3:string):Promise<rclone.MountInfo>;
+
+export function RemountToMountDirectory(arg1:Array<string>):Promise<void>;
+
+export function RemoveDataSource(arg1:string):Promise<void>;
+
+export function RenameDataSource(arg1:string,arg2:string):Promise<void>;
+
+export function ReportActivity():Promise<void>;
+
+export function RestoreConfigSnapshot(arg1:string):Promise<void>;
+
+export function RevealSecret(arg1:string,arg2:string):Promise<Record<string, string>>;
+
+export function SetAutoLock(arg1:number,arg2:string):Promise<void>;
 
 export function SetAutoStart(arg1:boolean):Promise<void>;
 
@@ -25,8 +71,26 @@ export function SetGistConfig(arg1:string,arg2:string):Promise<void>;
 
 export function SetMasterPassword(arg1:string):Promise<void>;
 
+export function SetMountAtStartup(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetMountDirectory(arg1:string):Promise<Array<rclone.MountInfo>>;
+
+export function SetRcloneLogLevel(arg1:string):Promise<void>;
+
+export function SetRememberPassword(arg1:boolean):Promise<void>;
+
 export function SyncToGist():Promise<void>;
 
-export function TestS3Connection(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string):Promise<void>;
+export function TestDataSource(arg1:config.DataSource):Promise<void>;
+
+export function TestS3Connection(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string,arg7:string):Promise<void>;
 
 export function Unmount(arg1:string):Promise<void>;
+
+export function UnwatchMountLog(arg1:string):Promise<void>;
+
+export function UpdateDataSource(arg1:config.DataSource):Promise<void>;
+
+export function UpdateS3DataSource(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string,arg7:string,arg8:string,arg9:string):Promise<void>;
+
+export function WatchMountLog(arg1:string):Promise<void>;
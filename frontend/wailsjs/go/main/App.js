@@ -2,38 +2,130 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function AddS3DataSource(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
-  return window['go']['main']['App']['AddS3DataSource'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+export function AddDataSource(arg1) {
+  return window['go']['main']['App']['AddDataSource'](arg1);
+}
+
+export function AddS3DataSource(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8) {
+  return window['go']['main']['App']['AddS3DataSource'](arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8);
+}
+
+export function ChangeMasterPassword(arg1, arg2) {
+  return window['go']['main']['App']['ChangeMasterPassword'](arg1, arg2);
+}
+
+export function ExportConfig(arg1, arg2) {
+  return window['go']['main']['App']['ExportConfig'](arg1, arg2);
+}
+
+export function GetAutoLock() {
+  return window['go']['main']['App']['GetAutoLock']();
+}
+
+export function GetDataSources() {
+  return window['go']['main']['App']['GetDataSources']();
+}
+
+export function GetDefaultMountOptions() {
+  return window['go']['main']['App']['GetDefaultMountOptions']();
 }
 
 export function GetGistConfig() {
   return window['go']['main']['App']['GetGistConfig']();
 }
 
+export function GetMountDirectory() {
+  return window['go']['main']['App']['GetMountDirectory']();
+}
+
+export function GetMountEntries() {
+  return window['go']['main']['App']['GetMountEntries']();
+}
+
+export function GetMountLog(arg1, arg2) {
+  return window['go']['main']['App']['GetMountLog'](arg1, arg2);
+}
+
 export function GetMounts() {
   return window['go']['main']['App']['GetMounts']();
 }
 
-export function GetS3DataSources() {
-  return window['go']['main']['App']['GetS3DataSources']();
+export function GetRcloneLogLevel() {
+  return window['go']['main']['App']['GetRcloneLogLevel']();
+}
+
+export function GetS3Providers() {
+  return window['go']['main']['App']['GetS3Providers']();
+}
+
+export function GetTransferStats() {
+  return window['go']['main']['App']['GetTransferStats']();
+}
+
+export function ImportConfig(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportConfig'](arg1, arg2, arg3);
 }
 
 export function IsAutoStartEnabled() {
   return window['go']['main']['App']['IsAutoStartEnabled']();
 }
 
+export function IsPasswordRemembered() {
+  return window['go']['main']['App']['IsPasswordRemembered']();
+}
+
 export function IsPasswordSet() {
   return window['go']['main']['App']['IsPasswordSet']();
 }
 
+export function ListConfigSnapshots() {
+  return window['go']['main']['App']['ListConfigSnapshots']();
+}
+
 export function ListFiles(arg1, arg2) {
   return window['go']['main']['App']['ListFiles'](arg1, arg2);
 }
 
+export function Lock() {
+  return window['go']['main']['App']['Lock']();
+}
+
 export function Mount(arg1, arg2) {
   return window['go']['main']['App']['Mount'](arg1, arg2);
 }
 
+export function MountAt(arg1, arg2, arg3) {
+  return window['go']['main']['App']['MountAt'](arg1, arg2, arg3);
+}
+
+export function RemountToMountDirectory(arg1) {
+  return window['go']['main']['App']['RemountToMountDirectory'](arg1);
+}
+
+export function RemoveDataSource(arg1) {
+  return window['go']['main']['App']['RemoveDataSource'](arg1);
+}
+
+export function RenameDataSource(arg1, arg2) {
+  return window['go']['main']['App']['RenameDataSource'](arg1, arg2);
+}
+
+export function ReportActivity() {
+  return window['go']['main']['App']['ReportActivity']();
+}
+
+export function RestoreConfigSnapshot(arg1) {
+  return window['go']['main']['App']['RestoreConfigSnapshot'](arg1);
+}
+
+export function RevealSecret(arg1, arg2) {
+  return window['go']['main']['App']['RevealSecret'](arg1, arg2);
+}
+
+export function SetAutoLock(arg1, arg2) {
+  return window['go']['main']['App']['SetAutoLock'](arg1, arg2);
+}
+
 export function SetAutoStart(arg1) {
   return window['go']['main']['App']['SetAutoStart'](arg1);
 }
@@ -46,14 +138,50 @@ export function SetMasterPassword(arg1) {
   return window['go']['main']['App']['SetMasterPassword'](arg1);
 }
 
+export function SetMountAtStartup(arg1, arg2) {
+  return window['go']['main']['App']['SetMountAtStartup'](arg1, arg2);
+}
+
+export function SetMountDirectory(arg1) {
+  return window['go']['main']['App']['SetMountDirectory'](arg1);
+}
+
+export function SetRcloneLogLevel(arg1) {
+  return window['go']['main']['App']['SetRcloneLogLevel'](arg1);
+}
+
+export function SetRememberPassword(arg1) {
+  return window['go']['main']['App']['SetRememberPassword'](arg1);
+}
+
 export function SyncToGist() {
   return window['go']['main']['App']['SyncToGist']();
 }
 
-export function TestS3Connection(arg1, arg2, arg3, arg4, arg5, arg6) {
-  return window['go']['main']['App']['TestS3Connection'](arg1, arg2, arg3, arg4, arg5, arg6);
+export function TestDataSource(arg1) {
+  return window['go']['main']['App']['TestDataSource'](arg1);
+}
+
+export function TestS3Connection(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['TestS3Connection'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
 }
 
 export function Unmount(arg1) {
   return window['go']['main']['App']['Unmount'](arg1);
 }
+
+export function UnwatchMountLog(arg1) {
+  return window['go']['main']['App']['UnwatchMountLog'](arg1);
+}
+
+export function UpdateDataSource(arg1) {
+  return window['go']['main']['App']['UpdateDataSource'](arg1);
+}
+
+export function UpdateS3DataSource(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9) {
+  return window['go']['main']['App']['UpdateS3DataSource'](arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9);
+}
+
+export function WatchMountLog(arg1) {
+  return window['go']['main']['App']['WatchMountLog'](arg1);
+}
@@ -1,14 +1,109 @@
 export namespace config {
 	
+	export class MountOptions {
+	    cacheMode: string;
+	    readOnly: boolean;
+	    uid?: number;
+	    gid?: number;
+	    umask: string;
+	    cacheMaxSizeMB: number;
+	    cacheMaxAgeMinutes: number;
+	    bufferSizeMB: number;
+	    dirCacheTimeSeconds: number;
+	    allowOther: boolean;
+	    allowNonEmpty: boolean;
+	    networkMode: boolean;
+	    readyTimeoutSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MountOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cacheMode = source["cacheMode"];
+	        this.readOnly = source["readOnly"];
+	        this.uid = source["uid"];
+	        this.gid = source["gid"];
+	        this.umask = source["umask"];
+	        this.cacheMaxSizeMB = source["cacheMaxSizeMB"];
+	        this.cacheMaxAgeMinutes = source["cacheMaxAgeMinutes"];
+	        this.bufferSizeMB = source["bufferSizeMB"];
+	        this.dirCacheTimeSeconds = source["dirCacheTimeSeconds"];
+	        this.allowOther = source["allowOther"];
+	        this.allowNonEmpty = source["allowNonEmpty"];
+	        this.networkMode = source["networkMode"];
+	        this.readyTimeoutSeconds = source["readyTimeoutSeconds"];
+	    }
+	}
+	export class RcloneConfig {
+	    backend: string;
+	    options: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new RcloneConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.backend = source["backend"];
+	        this.options = source["options"];
+	    }
+	}
+	export class SFTPConfig {
+	    host: string;
+	    port: number;
+	    user: string;
+	    password: string;
+	    keyFile: string;
+	    knownHostsFile: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SFTPConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.user = source["user"];
+	        this.password = source["password"];
+	        this.keyFile = source["keyFile"];
+	        this.knownHostsFile = source["knownHostsFile"];
+	    }
+	}
+	export class WebDAVConfig {
+	    url: string;
+	    vendor: string;
+	    user: string;
+	    password: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WebDAVConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.vendor = source["vendor"];
+	        this.user = source["user"];
+	        this.password = source["password"];
+	    }
+	}
 	export class S3Config {
-	    id: string;
-	    name: string;
+	    provider: string;
 	    endpoint: string;
 	    accessKey: string;
 	    secretKey: string;
 	    region: string;
 	    bucket: string;
-	    description: string;
+	    credentialMode: string;
+	    sessionToken: string;
+	    profile: string;
+	    roleArn: string;
+	    externalId: string;
+	    roleSessionName: string;
+	    stsEndpoint: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new S3Config(source);
@@ -16,16 +111,246 @@ export namespace config {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
+	        this.provider = source["provider"];
 	        this.endpoint = source["endpoint"];
 	        this.accessKey = source["accessKey"];
 	        this.secretKey = source["secretKey"];
 	        this.region = source["region"];
 	        this.bucket = source["bucket"];
+	        this.credentialMode = source["credentialMode"];
+	        this.sessionToken = source["sessionToken"];
+	        this.profile = source["profile"];
+	        this.roleArn = source["roleArn"];
+	        this.externalId = source["externalId"];
+	        this.roleSessionName = source["roleSessionName"];
+	        this.stsEndpoint = source["stsEndpoint"];
+	    }
+	}
+	export class DataSource {
+	    id: string;
+	    name: string;
+	    type: string;
+	    description: string;
+	    s3?: S3Config;
+	    webdav?: WebDAVConfig;
+	    sftp?: SFTPConfig;
+	    rclone?: RcloneConfig;
+	    mountOptions?: MountOptions;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.type = source["type"];
 	        this.description = source["description"];
+	        this.s3 = this.convertValues(source["s3"], S3Config);
+	        this.webdav = this.convertValues(source["webdav"], WebDAVConfig);
+	        this.sftp = this.convertValues(source["sftp"], SFTPConfig);
+	        this.rclone = this.convertValues(source["rclone"], RcloneConfig);
+	        this.mountOptions = this.convertValues(source["mountOptions"], MountOptions);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DataSourceCollision {
+	    name: string;
+	    identical: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataSourceCollision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.identical = source["identical"];
 	    }
 	}
+	export class ImportResult {
+	    mode: string;
+	    added: string[];
+	    collisions: DataSourceCollision[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.added = source["added"];
+	        this.collisions = this.convertValues(source["collisions"], DataSourceCollision);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MaskedDataSource {
+	    id: string;
+	    name: string;
+	    type: string;
+	    description: string;
+	    s3?: S3Config;
+	    webdav?: WebDAVConfig;
+	    sftp?: SFTPConfig;
+	    rclone?: RcloneConfig;
+	    mountOptions?: MountOptions;
+	    maskedSecrets: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaskedDataSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.description = source["description"];
+	        this.s3 = this.convertValues(source["s3"], S3Config);
+	        this.webdav = this.convertValues(source["webdav"], WebDAVConfig);
+	        this.sftp = this.convertValues(source["sftp"], SFTPConfig);
+	        this.rclone = this.convertValues(source["rclone"], RcloneConfig);
+	        this.mountOptions = this.convertValues(source["mountOptions"], MountOptions);
+	        this.maskedSecrets = source["maskedSecrets"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MountEntry {
+	    id: string;
+	    sourceId: string;
+	    remotePath: string;
+	    localPath: string;
+	    autoMount: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MountEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.sourceId = source["sourceId"];
+	        this.remotePath = source["remotePath"];
+	        this.localPath = source["localPath"];
+	        this.autoMount = source["autoMount"];
+	    }
+	}
+	
+	export class Provider {
+	    id: string;
+	    name: string;
+	    rcloneProvider: string;
+	    forcePathStyle: boolean;
+	    endpointTemplate: string;
+	    requiresEndpoint: boolean;
+	    defaultRegion: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Provider(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.rcloneProvider = source["rcloneProvider"];
+	        this.forcePathStyle = source["forcePathStyle"];
+	        this.endpointTemplate = source["endpointTemplate"];
+	        this.requiresEndpoint = source["requiresEndpoint"];
+	        this.defaultRegion = source["defaultRegion"];
+	    }
+	}
+	
+	
+	
+	export class Snapshot {
+	    id: string;
+	    // Go type: time
+	    createdAt: any;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Snapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.size = source["size"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
@@ -73,11 +398,17 @@ export namespace rclone {
 		}
 	}
 	export class MountInfo {
+	    id: string;
+	    sourceId: string;
 	    name: string;
 	    remote: string;
 	    localPath: string;
 	    pid: number;
 	    status: string;
+	    explicitPath: boolean;
+	    autoMount: boolean;
+	    restarts: number;
+	    lastError?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new MountInfo(source);
@@ -85,11 +416,51 @@ export namespace rclone {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.sourceId = source["sourceId"];
 	        this.name = source["name"];
 	        this.remote = source["remote"];
 	        this.localPath = source["localPath"];
 	        this.pid = source["pid"];
 	        this.status = source["status"];
+	        this.explicitPath = source["explicitPath"];
+	        this.autoMount = source["autoMount"];
+	        this.restarts = source["restarts"];
+	        this.lastError = source["lastError"];
+	    }
+	}
+	export class RCStats {
+	    bytes: number;
+	    checks: number;
+	    deletes: number;
+	    errors: number;
+	    fatalError: boolean;
+	    lastError: string;
+	    renames: number;
+	    speed: number;
+	    totalBytes: number;
+	    totalTransfers: number;
+	    transfers: number;
+	    elapsedTime: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RCStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bytes = source["bytes"];
+	        this.checks = source["checks"];
+	        this.deletes = source["deletes"];
+	        this.errors = source["errors"];
+	        this.fatalError = source["fatalError"];
+	        this.lastError = source["lastError"];
+	        this.renames = source["renames"];
+	        this.speed = source["speed"];
+	        this.totalBytes = source["totalBytes"];
+	        this.totalTransfers = source["totalTransfers"];
+	        this.transfers = source["transfers"];
+	        this.elapsedTime = source["elapsedTime"];
 	    }
 	}
 
@@ -0,0 +1,143 @@
+package keyring
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore 没有密钥环守护进程时使用的文件存储。
+// 文件权限为0600，安全性仅依赖于操作系统的文件权限。
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore 创建文件存储
+func NewFileStore(configDir string) *FileStore {
+	return &FileStore{
+		path: filepath.Join(configDir, "keyring.json"),
+	}
+}
+
+// Name 存储后端名称
+func (f *FileStore) Name() string {
+	return "file"
+}
+
+// Get 读取密钥
+func (f *FileStore) Get(account string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, ok := entries[account]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("密钥文件格式错误: %v", err)
+	}
+
+	return value, nil
+}
+
+// Has 检查密钥是否存在
+func (f *FileStore) Has(account string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := entries[account]
+	return ok, nil
+}
+
+// Set 保存密钥
+func (f *FileStore) Set(account string, value []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	entries[account] = base64.StdEncoding.EncodeToString(value)
+	return f.save(entries)
+}
+
+// Delete 删除密钥
+func (f *FileStore) Delete(account string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := entries[account]; !ok {
+		return nil
+	}
+
+	delete(entries, account)
+	if len(entries) == 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除密钥文件失败: %v", err)
+		}
+		return nil
+	}
+
+	return f.save(entries)
+}
+
+// load 读取密钥文件
+func (f *FileStore) load() (map[string]string, error) {
+	entries := make(map[string]string)
+
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥文件失败: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("密钥文件格式错误: %v", err)
+	}
+
+	return entries, nil
+}
+
+// save 写入密钥文件
+func (f *FileStore) save(entries map[string]string) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("序列化密钥失败: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return fmt.Errorf("创建配置目录失败: %v", err)
+	}
+
+	if err := ioutil.WriteFile(f.path, data, 0600); err != nil {
+		return fmt.Errorf("写入密钥文件失败: %v", err)
+	}
+
+	return nil
+}
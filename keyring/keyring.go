@@ -0,0 +1,32 @@
+package keyring
+
+import (
+	"errors"
+)
+
+// ErrNotFound 密钥库中不存在该条目
+var ErrNotFound = errors.New("密钥库中未找到该条目")
+
+// KeyStore 本机密钥存储
+type KeyStore interface {
+	// Get 读取密钥，不存在时返回 ErrNotFound
+	Get(account string) ([]byte, error)
+	// Has 检查密钥是否存在，不读取密钥，也不会触发密钥环解锁
+	Has(account string) (bool, error)
+	// Set 保存密钥，已存在时覆盖
+	Set(account string, secret []byte) error
+	// Delete 删除密钥，不存在时不报错
+	Delete(account string) error
+	// Name 存储后端名称，用于界面展示
+	Name() string
+}
+
+// New 优先使用系统的 Secret Service 密钥环，不可用时（例如没有运行密钥环守护进程）
+// 回退到配置目录下的文件存储
+func New(appName, configDir string) KeyStore {
+	if store, err := NewSecretServiceStore(appName); err == nil {
+		return store
+	}
+
+	return NewFileStore(configDir)
+}
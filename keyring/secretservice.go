@@ -0,0 +1,236 @@
+package keyring
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	secretServiceName     = "org.freedesktop.secrets"
+	secretServicePath     = dbus.ObjectPath("/org/freedesktop/secrets")
+	defaultCollection     = dbus.ObjectPath("/org/freedesktop/secrets/aliases/default")
+	secretServiceIface    = "org.freedesktop.Secret.Service"
+	secretCollectionIface = "org.freedesktop.Secret.Collection"
+	secretItemIface       = "org.freedesktop.Secret.Item"
+	secretPromptIface     = "org.freedesktop.Secret.Prompt"
+
+	// 用户在系统对话框中解锁密钥环的最长等待时间
+	promptTimeout = 2 * time.Minute
+)
+
+// secret Secret Service 规范中的 Secret 结构 (oayays)
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// SecretServiceStore 基于 freedesktop Secret Service (D-Bus) 的密钥存储，
+// GNOME Keyring 和 KWallet 都实现了该接口
+type SecretServiceStore struct {
+	appName string
+	conn    *dbus.Conn
+	service dbus.BusObject
+	session dbus.ObjectPath
+}
+
+// NewSecretServiceStore 连接会话总线并打开 Secret Service 会话
+func NewSecretServiceStore(appName string) (*SecretServiceStore, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("连接D-Bus会话总线失败: %v", err)
+	}
+
+	service := conn.Object(secretServiceName, secretServicePath)
+
+	// 使用plain算法，密钥只在本机会话总线上传输
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	err = service.Call(secretServiceIface+".OpenSession", 0, "plain", dbus.MakeVariant("")).Store(&output, &session)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("打开Secret Service会话失败: %v", err)
+	}
+
+	return &SecretServiceStore{
+		appName: appName,
+		conn:    conn,
+		service: service,
+		session: session,
+	}, nil
+}
+
+// Name 存储后端名称
+func (s *SecretServiceStore) Name() string {
+	return "secret-service"
+}
+
+// attributes 用于查找条目的属性
+func (s *SecretServiceStore) attributes(account string) map[string]string {
+	return map[string]string{
+		"application": s.appName,
+		"account":     account,
+	}
+}
+
+// Get 读取密钥
+func (s *SecretServiceStore) Get(account string) ([]byte, error) {
+	item, err := s.findItem(account)
+	if err != nil {
+		return nil, err
+	}
+
+	var sec secret
+	err = s.conn.Object(secretServiceName, item).Call(secretItemIface+".GetSecret", 0, s.session).Store(&sec)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥失败: %v", err)
+	}
+
+	return sec.Value, nil
+}
+
+// Has 检查密钥是否存在。锁定的条目同样计入，不解锁也不弹出系统对话框
+func (s *SecretServiceStore) Has(account string) (bool, error) {
+	unlocked, locked, err := s.searchItems(account)
+	if err != nil {
+		return false, err
+	}
+	return len(unlocked) > 0 || len(locked) > 0, nil
+}
+
+// Set 保存密钥
+func (s *SecretServiceStore) Set(account string, value []byte) error {
+	if err := s.unlock(defaultCollection); err != nil {
+		return err
+	}
+
+	props := map[string]dbus.Variant{
+		"org.freedesktop.Secret.Item.Label":      dbus.MakeVariant(fmt.Sprintf("%s (%s)", s.appName, account)),
+		"org.freedesktop.Secret.Item.Attributes": dbus.MakeVariant(s.attributes(account)),
+	}
+	sec := secret{
+		Session:     s.session,
+		Value:       value,
+		ContentType: "application/octet-stream",
+	}
+
+	var item, prompt dbus.ObjectPath
+	collection := s.conn.Object(secretServiceName, defaultCollection)
+	err := collection.Call(secretCollectionIface+".CreateItem", 0, props, sec, true).Store(&item, &prompt)
+	if err != nil {
+		return fmt.Errorf("保存密钥失败: %v", err)
+	}
+
+	return s.waitPrompt(prompt)
+}
+
+// Delete 删除密钥
+func (s *SecretServiceStore) Delete(account string) error {
+	item, err := s.findItem(account)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var prompt dbus.ObjectPath
+	err = s.conn.Object(secretServiceName, item).Call(secretItemIface+".Delete", 0).Store(&prompt)
+	if err != nil {
+		return fmt.Errorf("删除密钥失败: %v", err)
+	}
+
+	return s.waitPrompt(prompt)
+}
+
+// searchItems 按属性查找条目，分别返回已解锁和锁定的条目
+func (s *SecretServiceStore) searchItems(account string) ([]dbus.ObjectPath, []dbus.ObjectPath, error) {
+	var unlocked, locked []dbus.ObjectPath
+	err := s.service.Call(secretServiceIface+".SearchItems", 0, s.attributes(account)).Store(&unlocked, &locked)
+	if err != nil {
+		return nil, nil, fmt.Errorf("查找密钥失败: %v", err)
+	}
+	return unlocked, locked, nil
+}
+
+// findItem 查找条目并在需要时解锁
+func (s *SecretServiceStore) findItem(account string) (dbus.ObjectPath, error) {
+	unlocked, locked, err := s.searchItems(account)
+	if err != nil {
+		return "", err
+	}
+
+	if len(unlocked) > 0 {
+		return unlocked[0], nil
+	}
+
+	if len(locked) > 0 {
+		if err := s.unlock(locked[0]); err != nil {
+			return "", err
+		}
+		return locked[0], nil
+	}
+
+	return "", ErrNotFound
+}
+
+// unlock 解锁条目或集合，必要时由密钥环弹出系统对话框
+func (s *SecretServiceStore) unlock(path dbus.ObjectPath) error {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	err := s.service.Call(secretServiceIface+".Unlock", 0, []dbus.ObjectPath{path}).Store(&unlocked, &prompt)
+	if err != nil {
+		return fmt.Errorf("解锁密钥环失败: %v", err)
+	}
+
+	return s.waitPrompt(prompt)
+}
+
+// waitPrompt 执行 Secret Service 返回的提示并等待用户完成
+func (s *SecretServiceStore) waitPrompt(prompt dbus.ObjectPath) error {
+	if prompt == "" || prompt == "/" {
+		return nil
+	}
+
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(prompt),
+		dbus.WithMatchInterface(secretPromptIface),
+		dbus.WithMatchMember("Completed"),
+	}
+	if err := s.conn.AddMatchSignal(match...); err != nil {
+		return fmt.Errorf("监听密钥环提示失败: %v", err)
+	}
+	defer s.conn.RemoveMatchSignal(match...)
+
+	signals := make(chan *dbus.Signal, 1)
+	s.conn.Signal(signals)
+	defer s.conn.RemoveSignal(signals)
+
+	if err := s.conn.Object(secretServiceName, prompt).Call(secretPromptIface+".Prompt", 0, "").Err; err != nil {
+		return fmt.Errorf("显示密钥环提示失败: %v", err)
+	}
+
+	timeout := time.After(promptTimeout)
+	for {
+		select {
+		case sig := <-signals:
+			if sig.Path != prompt || len(sig.Body) == 0 {
+				continue
+			}
+			if dismissed, ok := sig.Body[0].(bool); ok && dismissed {
+				return fmt.Errorf("用户取消了密钥环解锁")
+			}
+			return nil
+		case <-timeout:
+			return fmt.Errorf("等待密钥环解锁超时")
+		}
+	}
+}
+
+// Close 关闭D-Bus连接
+func (s *SecretServiceStore) Close() error {
+	return s.conn.Close()
+}
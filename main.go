@@ -2,10 +2,13 @@ package main
 
 import (
 	"embed"
+	"errors"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
+
+	"rmount/config"
 )
 
 //go:embed all:frontend/dist
@@ -25,6 +28,7 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 0, G: 0, B: 0, A: 0}, // 透明背景
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
 		Frameless:        true,
 		DisableResize:    false,
 		Fullscreen:       false,
@@ -32,9 +36,27 @@ func main() {
 		Bind: []interface{}{
 			app,
 		},
+		ErrorFormatter: formatError,
+		// 多个GUI实例会各自持有配置和挂载状态，第二个实例启动时改为激活已有窗口
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId:               "com.rmount.app",
+			OnSecondInstanceLaunch: app.onSecondInstanceLaunch,
+		},
 	})
 
 	if err != nil {
 		println("Error:", err.Error())
 	}
 }
+
+// formatError 校验错误以结构化对象返回给前端，便于按字段展示，其余错误保持字符串
+func formatError(err error) any {
+	var verr *config.ValidationError
+	if errors.As(err, &verr) {
+		return map[string]any{
+			"message": verr.Error(),
+			"fields":  verr.Fields,
+		}
+	}
+	return err.Error()
+}
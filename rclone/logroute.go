@@ -0,0 +1,199 @@
+package rclone
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logRoutePollInterval 读取rclone日志新增内容的间隔
+const logRoutePollInterval = time.Second
+
+// logRoute 挂载的日志分拣规则
+type logRoute struct {
+	// remote rclone remote名称加冒号，vfs等模块的日志以此开头
+	remote string
+	// mountPoint FUSE层的日志包含挂载点路径
+	mountPoint string
+}
+
+// logRouter 将rc服务的共用日志按挂载分拣到各自的日志文件。
+// rclone的日志文件是进程级的，无法为同一进程中的每个挂载单独指定 --log-file，
+// 因此包含remote名称或挂载点的行写入对应挂载的日志；只有一个挂载时，
+// 无法识别归属的行（例如只带相对路径的文件错误）也写入该挂载的日志
+type logRouter struct {
+	configDir string
+
+	mutex  sync.Mutex
+	routes map[string]logRoute
+	// offset 共用日志中已分拣到的位置
+	offset  int64
+	started bool
+	stop    chan struct{}
+}
+
+// newLogRouter 创建日志分拣器，第一次添加规则时开始读取共用日志
+func newLogRouter(configDir string) *logRouter {
+	return &logRouter{
+		configDir: configDir,
+		routes:    make(map[string]logRoute),
+		stop:      make(chan struct{}),
+	}
+}
+
+// add 开始为挂载分拣日志
+func (r *logRouter) add(mountID, remoteName, mountPoint string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.started {
+		r.started = true
+		// 只分拣之后写入的内容，旧内容可能属于已删除的挂载
+		if info, err := os.Stat(RcloneLogPath(r.configDir)); err == nil {
+			r.offset = info.Size()
+		}
+		go r.run()
+	}
+
+	r.routes[mountID] = logRoute{remote: remoteName + ":", mountPoint: mountPoint}
+}
+
+// remove 分拣完已写入的内容后停止为挂载分拣日志
+func (r *logRouter) remove(mountID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.poll()
+	delete(r.routes, mountID)
+}
+
+// flush 立即分拣已写入的内容，读取挂载日志前调用
+func (r *logRouter) flush() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.poll()
+}
+
+// event 向挂载日志写入rmount自身的事件，格式与rclone日志一致
+func (r *logRouter) event(mountID, format string, args ...interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.poll()
+	line := fmt.Sprintf("%s NOTICE : rmount: %s", time.Now().Format("2006/01/02 15:04:05"), fmt.Sprintf(format, args...))
+	r.write(mountID, []string{line})
+}
+
+// close 停止分拣
+func (r *logRouter) close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+}
+
+func (r *logRouter) run() {
+	ticker := time.NewTicker(logRoutePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+
+		r.mutex.Lock()
+		r.poll()
+		for mountID := range r.routes {
+			if err := rotateLog(LogPath(r.configDir, mountID)); err != nil {
+				fmt.Printf("%v\n", err)
+			}
+		}
+		r.mutex.Unlock()
+	}
+}
+
+// poll 读取共用日志新增的完整行并分拣，调用方需持有 mutex
+func (r *logRouter) poll() {
+	path := RcloneLogPath(r.configDir)
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	// 共用日志轮转后从头读取
+	if info.Size() < r.offset {
+		r.offset = 0
+	}
+	if info.Size() == r.offset {
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	data := make([]byte, info.Size()-r.offset)
+	n, _ := f.ReadAt(data, r.offset)
+	data = data[:n]
+
+	// 只处理完整的行，未写完的行留到下次读取
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		return
+	}
+	r.offset += int64(end + 1)
+
+	routed := make(map[string][]string)
+	for _, line := range strings.Split(string(data[:end]), "\n") {
+		for _, mountID := range r.match(line) {
+			routed[mountID] = append(routed[mountID], line)
+		}
+	}
+	for mountID, lines := range routed {
+		r.write(mountID, lines)
+	}
+}
+
+// match 返回日志行所属的挂载
+func (r *logRouter) match(line string) []string {
+	var matched []string
+	for mountID, route := range r.routes {
+		if strings.Contains(line, route.remote) || strings.Contains(line, route.mountPoint) {
+			matched = append(matched, mountID)
+		}
+	}
+
+	if len(matched) == 0 && len(r.routes) == 1 {
+		for mountID := range r.routes {
+			matched = append(matched, mountID)
+		}
+	}
+	return matched
+}
+
+// write 追加到挂载日志
+func (r *logRouter) write(mountID string, lines []string) {
+	path := LogPath(r.configDir, mountID)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	f.WriteString(strings.Join(lines, "\n") + "\n")
+}
@@ -0,0 +1,139 @@
+package rclone
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxLogSize 日志超过该大小时轮转
+	maxLogSize = 10 << 20
+	// maxLogBackups 保留的历史日志数量: <id>.log.1 到 <id>.log.3
+	maxLogBackups = 3
+	// maxLogTailBytes 读取日志末尾时最多读取的字节数
+	maxLogTailBytes = 1 << 20
+	// mountLogTailLines 挂载失败时附带的日志行数
+	mountLogTailLines = 20
+)
+
+// CheckMountID 检查挂载ID。挂载ID会拼接到日志路径中，只接受UUID，避免访问日志目录以外的文件
+func CheckMountID(mountID string) error {
+	if _, err := uuid.Parse(mountID); err != nil {
+		return fmt.Errorf("无效的挂载ID: %q", mountID)
+	}
+	return nil
+}
+
+// LogPath 返回挂载日志路径，内容由 logRouter 从rclone日志中分拣
+func LogPath(configDir, mountID string) string {
+	return filepath.Join(configDir, "logs", mountID+".log")
+}
+
+// RcloneLogPath 返回rc服务的完整日志路径
+func RcloneLogPath(configDir string) string {
+	return filepath.Join(configDir, "logs", "rclone.log")
+}
+
+// removeLogFiles 删除日志及其轮转的历史文件
+func removeLogFiles(path string) error {
+	paths := []string{path}
+	for i := 1; i <= maxLogBackups; i++ {
+		paths = append(paths, fmt.Sprintf("%s.%d", path, i))
+	}
+
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除日志失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// rotateLog 日志超过 maxLogSize 时复制为 .1 并清空原文件。
+// rclone以追加方式打开日志文件，清空后继续从文件开头写入，不需要重新打开
+func rotateLog(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() < maxLogSize {
+		return nil
+	}
+
+	for i := maxLogBackups - 1; i >= 1; i-- {
+		older := fmt.Sprintf("%s.%d", path, i)
+		if err := os.Rename(older, fmt.Sprintf("%s.%d", path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("轮转日志失败: %v", err)
+		}
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("轮转日志失败: %v", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".1", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("轮转日志失败: %v", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("轮转日志失败: %v", err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("轮转日志失败: %v", err)
+	}
+
+	if err := os.Truncate(path, 0); err != nil {
+		return fmt.Errorf("轮转日志失败: %v", err)
+	}
+	return nil
+}
+
+// ReadLogTail 读取日志最后 lines 行，日志不存在时返回空
+func ReadLogTail(path string, lines int) ([]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取日志失败: %v", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("读取日志失败: %v", err)
+	}
+
+	offset := info.Size() - maxLogTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	data := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(data, offset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("读取日志失败: %v", err)
+	}
+
+	result := splitLogLines(string(data))
+	// 从文件中间开始读取时第一行不完整
+	if offset > 0 && len(result) > 0 {
+		result = result[1:]
+	}
+	if lines > 0 && len(result) > lines {
+		result = result[len(result)-lines:]
+	}
+	return result, nil
+}
+
+// splitLogLines 按行拆分日志，忽略末尾的空行
+func splitLogLines(data string) []string {
+	data = strings.TrimRight(data, "\n")
+	if data == "" {
+		return []string{}
+	}
+	return strings.Split(data, "\n")
+}
@@ -0,0 +1,14 @@
+package rclone
+
+import "testing"
+
+func TestCheckMountID(t *testing.T) {
+	for _, id := range []string{"", "../config", "../../etc/passwd", "rclone", "abc/def"} {
+		if err := CheckMountID(id); err == nil {
+			t.Errorf("CheckMountID(%q) accepted an ID that is not a UUID", id)
+		}
+	}
+	if err := CheckMountID("0b7c1c9e-5d2f-4e0a-9c53-6f0f6f8a3d21"); err != nil {
+		t.Errorf("CheckMountID rejected a UUID: %v", err)
+	}
+}
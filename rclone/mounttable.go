@@ -0,0 +1,145 @@
+package rclone
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// 挂载状态
+const (
+	MountStatusMounted = "mounted"
+	// MountStatusStale rclone进程已退出但挂载点仍在，访问时返回 "Transport endpoint is not connected"
+	MountStatusStale = "stale"
+	// MountStatusRestarting 挂载失效，正在重新挂载
+	MountStatusRestarting = "restarting"
+	// MountStatusFailed 多次重新挂载失败后放弃
+	MountStatusFailed = "failed"
+)
+
+// rcloneFSType rclone在Linux挂载表中使用的文件系统类型
+const rcloneFSType = "fuse.rclone"
+
+// mountInfoPath Linux当前进程可见的挂载表
+const mountInfoPath = "/proc/self/mountinfo"
+
+// MountTableEntry 挂载表中的一项
+type MountTableEntry struct {
+	MountPoint string
+	FSType     string
+	// Source rclone挂载时为 remote:path
+	Source string
+}
+
+// ReadMountTable 读取系统挂载表。Linux解析 /proc/self/mountinfo，其他系统解析 mount 命令输出
+func ReadMountTable() ([]MountTableEntry, error) {
+	f, err := os.Open(mountInfoPath)
+	if os.IsNotExist(err) {
+		output, err := exec.Command("mount").Output()
+		if err != nil {
+			return nil, fmt.Errorf("读取挂载表失败: %v", err)
+		}
+		return parseMountOutput(string(output)), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取挂载表失败: %v", err)
+	}
+	defer f.Close()
+
+	return parseMountInfo(f)
+}
+
+// parseMountInfo 解析 mountinfo 格式:
+// 36 35 98:0 /root /mnt/point rw,noatime master:1 - fuse.rclone remote:path rw,user_id=0
+func parseMountInfo(r io.Reader) ([]MountTableEntry, error) {
+	var entries []MountTableEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		// 可选字段数量不定，以单独的 "-" 分隔
+		sep := -1
+		for i, field := range fields {
+			if field == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 5 || sep+2 >= len(fields) {
+			continue
+		}
+
+		entries = append(entries, MountTableEntry{
+			MountPoint: unescapeMountField(fields[4]),
+			FSType:     fields[sep+1],
+			Source:     unescapeMountField(fields[sep+2]),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("解析挂载表失败: %v", err)
+	}
+	return entries, nil
+}
+
+// unescapeMountField 还原挂载表中转义的空格、制表符、换行和反斜杠，例如 \040
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// mountLinePattern macOS mount 命令的输出格式: remote:path on /mount/point (macfuse, nodev, ...)
+var mountLinePattern = regexp.MustCompile(`^(.+) on (.+) \(([^,)]+)`)
+
+// parseMountOutput 解析 mount 命令输出，FUSE挂载的类型统一记为 fuse.rclone 以便后续按source判断
+func parseMountOutput(output string) []MountTableEntry {
+	var entries []MountTableEntry
+	for _, line := range strings.Split(output, "\n") {
+		m := mountLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		fsType := m[3]
+		if strings.Contains(fsType, "fuse") {
+			fsType = rcloneFSType
+		}
+		entries = append(entries, MountTableEntry{
+			MountPoint: m[2],
+			FSType:     fsType,
+			Source:     m[1],
+		})
+	}
+	return entries
+}
+
+// mountStatus 检查挂载点是否仍可访问，FUSE进程无响应时 stat 会阻塞，超时同样视为失效
+func mountStatus(mountPoint string) string {
+	err := statWithTimeout(mountPoint, mountStatTimeout)
+	if errors.Is(err, syscall.ENOTCONN) || errors.Is(err, errStatTimeout) {
+		return MountStatusStale
+	}
+	return MountStatusMounted
+}
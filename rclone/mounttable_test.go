@@ -0,0 +1,66 @@
+package rclone
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMountInfo(t *testing.T) {
+	const mountinfo = `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+36 22 0:32 / /home/alice/mnt/photos rw,nosuid,nodev,relatime shared:42 master:7 - fuse.rclone photos:2024 rw,user_id=1000,group_id=1000
+37 22 0:33 / /home/alice/mnt/my\040drive rw,nosuid,nodev,relatime - fuse.rclone my\040drive: rw,user_id=1000,group_id=1000
+38 22 0:34 / /run/user/1000/doc rw,nosuid,nodev,relatime shared:300 - fuse.portal portal rw,user_id=1000,group_id=1000
+39 22 0:35 / /broken rw
+`
+
+	entries, err := parseMountInfo(strings.NewReader(mountinfo))
+	if err != nil {
+		t.Fatalf("parseMountInfo: %v", err)
+	}
+
+	want := []MountTableEntry{
+		{MountPoint: "/", FSType: "ext4", Source: "/dev/sda1"},
+		{MountPoint: "/home/alice/mnt/photos", FSType: rcloneFSType, Source: "photos:2024"},
+		{MountPoint: "/home/alice/mnt/my drive", FSType: rcloneFSType, Source: "my drive:"},
+		{MountPoint: "/run/user/1000/doc", FSType: "fuse.portal", Source: "portal"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("parseMountInfo =\n%+v\nwant\n%+v", entries, want)
+	}
+}
+
+func TestUnescapeMountField(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "/mnt/plain", want: "/mnt/plain"},
+		{in: `/mnt/a\040b`, want: "/mnt/a b"},
+		{in: `/mnt/tab\011end`, want: "/mnt/tab\tend"},
+		{in: `/mnt/back\134slash`, want: `/mnt/back\slash`},
+		{in: `/mnt/end\040`, want: "/mnt/end "},
+		{in: `/mnt/short\04`, want: `/mnt/short\04`},
+		{in: `/mnt/not\0x1`, want: `/mnt/not\0x1`},
+	}
+
+	for _, tt := range tests {
+		if got := unescapeMountField(tt.in); got != tt.want {
+			t.Errorf("unescapeMountField(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseMountOutput(t *testing.T) {
+	const output = `/dev/disk3s1s1 on / (apfs, sealed, local, read-only, journaled)
+photos:2024 on /Users/alice/mnt/photos (macfuse, nodev, nosuid, synchronous, mounted by alice)
+`
+
+	want := []MountTableEntry{
+		{MountPoint: "/", FSType: "apfs", Source: "/dev/disk3s1s1"},
+		{MountPoint: "/Users/alice/mnt/photos", FSType: rcloneFSType, Source: "photos:2024"},
+	}
+	if got := parseMountOutput(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMountOutput =\n%+v\nwant\n%+v", got, want)
+	}
+}
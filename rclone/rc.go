@@ -0,0 +1,250 @@
+package rclone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"rmount/config"
+)
+
+// rcRequestTimeout 单个rc请求的超时时间，mount/mount 需要等待FUSE挂载完成，因此留得较宽
+const rcRequestTimeout = 60 * time.Second
+
+// RCClient rclone远程控制(rc) API客户端
+type RCClient struct {
+	// endpoint rc服务地址，例如 http://localhost，末尾不带 /
+	endpoint   string
+	user       string
+	token      string
+	httpClient *http.Client
+}
+
+// RCError rc API返回的错误
+type RCError struct {
+	Method string
+	Status int
+	// Message rclone返回的错误信息
+	Message string
+}
+
+// Error 实现error接口
+func (e *RCError) Error() string {
+	return fmt.Sprintf("rclone %s 失败 (HTTP %d): %s", e.Method, e.Status, e.Message)
+}
+
+// RCMount mount/listmounts 返回的挂载
+type RCMount struct {
+	Fs         string    `json:"Fs"`
+	MountPoint string    `json:"MountPoint"`
+	MountedOn  time.Time `json:"MountedOn"`
+}
+
+// RCStats core/stats 返回的传输统计
+type RCStats struct {
+	Bytes          int64   `json:"bytes"`
+	Checks         int64   `json:"checks"`
+	Deletes        int64   `json:"deletes"`
+	Errors         int64   `json:"errors"`
+	FatalError     bool    `json:"fatalError"`
+	LastError      string  `json:"lastError"`
+	Renames        int64   `json:"renames"`
+	Speed          float64 `json:"speed"`
+	TotalBytes     int64   `json:"totalBytes"`
+	TotalTransfers int64   `json:"totalTransfers"`
+	Transfers      int64   `json:"transfers"`
+	ElapsedTime    float64 `json:"elapsedTime"`
+}
+
+// NewRCClient 创建rc客户端。httpClient 为空时使用默认客户端，
+// 测试时可以传入 httptest.Server 的地址和客户端
+func NewRCClient(endpoint, user, token string, httpClient *http.Client) *RCClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: rcRequestTimeout}
+	}
+	return &RCClient{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		user:       user,
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+// NewUnixRCClient 创建通过Unix套接字连接rc服务的客户端
+func NewUnixRCClient(socketPath, user, token string) *RCClient {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socketPath)
+		},
+	}
+	// 主机名只用于拼接URL，实际连接走套接字
+	return NewRCClient("http://localhost", user, token, &http.Client{
+		Transport: transport,
+		Timeout:   rcRequestTimeout,
+	})
+}
+
+// call 调用rc方法，in 和 out 均为JSON对象
+func (c *RCClient) call(ctx context.Context, method string, in, out interface{}) error {
+	if in == nil {
+		in = map[string]interface{}{}
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("序列化rc请求失败: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建rc请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.user != "" || c.token != "" {
+		req.SetBasicAuth(c.user, c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("连接rclone rc服务失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取rc响应失败: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		rcErr := &RCError{Method: method, Status: resp.StatusCode}
+		var errBody struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &errBody) == nil && errBody.Error != "" {
+			rcErr.Message = errBody.Error
+		} else {
+			rcErr.Message = strings.TrimSpace(string(data))
+		}
+		return rcErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("解析rc响应失败: %v", err)
+	}
+	return nil
+}
+
+// Noop 调用 rc/noop，用于检查rc服务是否可用以及认证是否正确
+func (c *RCClient) Noop(ctx context.Context) error {
+	return c.call(ctx, "rc/noop", nil, nil)
+}
+
+// Mount 调用 mount/mount 将 fs 挂载到 mountPoint
+func (c *RCClient) Mount(ctx context.Context, fs, mountPoint string, opts config.MountOptions) error {
+	mountOpt, vfsOpt, mainOpt := MountRCOptions(opts)
+	in := map[string]interface{}{
+		"fs":         fs,
+		"mountPoint": mountPoint,
+		"mountOpt":   mountOpt,
+		"vfsOpt":     vfsOpt,
+	}
+	if len(mainOpt) > 0 {
+		in["_config"] = mainOpt
+	}
+	return c.call(ctx, "mount/mount", in, nil)
+}
+
+// Unmount 调用 mount/unmount 卸载 mountPoint
+func (c *RCClient) Unmount(ctx context.Context, mountPoint string) error {
+	return c.call(ctx, "mount/unmount", map[string]interface{}{"mountPoint": mountPoint}, nil)
+}
+
+// ListMounts 调用 mount/listmounts 列出rc服务持有的挂载
+func (c *RCClient) ListMounts(ctx context.Context) ([]RCMount, error) {
+	var out struct {
+		MountPoints []RCMount `json:"mountPoints"`
+	}
+	if err := c.call(ctx, "mount/listmounts", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.MountPoints, nil
+}
+
+// Stats 调用 core/stats 获取传输统计
+func (c *RCClient) Stats(ctx context.Context) (*RCStats, error) {
+	var stats RCStats
+	if err := c.call(ctx, "core/stats", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// SetLogLevel 调用 options/set 修改rclone日志级别
+func (c *RCClient) SetLogLevel(ctx context.Context, level string) error {
+	in := map[string]interface{}{
+		"main": map[string]interface{}{"LogLevel": level},
+	}
+	return c.call(ctx, "options/set", in, nil)
+}
+
+// MountRCOptions 将挂载参数转换为 mount/mount 的 mountOpt、vfsOpt 以及 _config 全局选项，
+// 数值为0的项使用rclone默认值
+func MountRCOptions(opts config.MountOptions) (mountOpt, vfsOpt, mainOpt map[string]interface{}) {
+	mountOpt = map[string]interface{}{}
+	vfsOpt = map[string]interface{}{}
+	mainOpt = map[string]interface{}{}
+
+	cacheMode := opts.CacheMode
+	if cacheMode == "" {
+		cacheMode = config.CacheModeFull
+	}
+	vfsOpt["CacheMode"] = cacheMode
+
+	if opts.ReadOnly {
+		vfsOpt["ReadOnly"] = true
+	}
+	if opts.UID != nil {
+		vfsOpt["UID"] = *opts.UID
+	}
+	if opts.GID != nil {
+		vfsOpt["GID"] = *opts.GID
+	}
+	if opts.Umask != "" {
+		if umask, err := strconv.ParseUint(opts.Umask, 8, 32); err == nil {
+			vfsOpt["Umask"] = umask
+		}
+	}
+	if opts.CacheMaxSizeMB > 0 {
+		vfsOpt["CacheMaxSize"] = fmt.Sprintf("%dM", opts.CacheMaxSizeMB)
+	}
+	if opts.CacheMaxAgeMinutes > 0 {
+		vfsOpt["CacheMaxAge"] = fmt.Sprintf("%dm", opts.CacheMaxAgeMinutes)
+	}
+	if opts.DirCacheTimeSeconds > 0 {
+		vfsOpt["DirCacheTime"] = fmt.Sprintf("%ds", opts.DirCacheTimeSeconds)
+	}
+	if opts.BufferSizeMB > 0 {
+		mainOpt["BufferSize"] = fmt.Sprintf("%dM", opts.BufferSizeMB)
+	}
+	if opts.AllowOther {
+		mountOpt["AllowOther"] = true
+	}
+	if opts.AllowNonEmpty {
+		mountOpt["AllowNonEmpty"] = true
+	}
+	if opts.NetworkMode {
+		mountOpt["NetworkMode"] = true
+	}
+
+	return mountOpt, vfsOpt, mainOpt
+}
@@ -0,0 +1,141 @@
+package rclone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"rmount/config"
+)
+
+func TestRCClientBasicAuth(t *testing.T) {
+	var user, pass string
+	var ok bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok = r.BasicAuth()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewRCClient(server.URL, "rmount", "secret-token", server.Client())
+	if err := client.Noop(context.Background()); err != nil {
+		t.Fatalf("Noop: %v", err)
+	}
+	if !ok || user != "rmount" || pass != "secret-token" {
+		t.Errorf("basic auth = %q/%q (set %v), want rmount/secret-token", user, pass, ok)
+	}
+}
+
+func TestRCClientMountPayload(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("request body is not JSON: %v", err)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	uid := 1000
+	opts := config.MountOptions{
+		CacheMode:      config.CacheModeWrites,
+		ReadOnly:       true,
+		UID:            &uid,
+		Umask:          "022",
+		CacheMaxSizeMB: 512,
+		BufferSizeMB:   32,
+		AllowOther:     true,
+	}
+
+	client := NewRCClient(server.URL, "", "", server.Client())
+	if err := client.Mount(context.Background(), "source:bucket/dir", "/mnt/source", opts); err != nil {
+		t.Fatalf("Mount: %v", err)
+	}
+
+	if path != "/mount/mount" {
+		t.Errorf("path = %q, want /mount/mount", path)
+	}
+	if body["fs"] != "source:bucket/dir" || body["mountPoint"] != "/mnt/source" {
+		t.Errorf("fs/mountPoint = %v/%v", body["fs"], body["mountPoint"])
+	}
+
+	vfsOpt, _ := body["vfsOpt"].(map[string]interface{})
+	wantVFS := map[string]interface{}{
+		"CacheMode":    config.CacheModeWrites,
+		"ReadOnly":     true,
+		"UID":          float64(1000),
+		"Umask":        float64(0o22),
+		"CacheMaxSize": "512M",
+	}
+	for key, want := range wantVFS {
+		if vfsOpt[key] != want {
+			t.Errorf("vfsOpt[%s] = %v, want %v", key, vfsOpt[key], want)
+		}
+	}
+	if _, ok := vfsOpt["GID"]; ok {
+		t.Error("vfsOpt contains GID although it is not set")
+	}
+
+	mountOpt, _ := body["mountOpt"].(map[string]interface{})
+	if mountOpt["AllowOther"] != true {
+		t.Errorf("mountOpt[AllowOther] = %v, want true", mountOpt["AllowOther"])
+	}
+	if _, ok := mountOpt["AllowNonEmpty"]; ok {
+		t.Error("mountOpt contains AllowNonEmpty although it is not set")
+	}
+
+	mainOpt, _ := body["_config"].(map[string]interface{})
+	if mainOpt["BufferSize"] != "32M" {
+		t.Errorf("_config[BufferSize] = %v, want 32M", mainOpt["BufferSize"])
+	}
+}
+
+func TestRCClientError(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		message string
+	}{
+		{
+			name:    "json",
+			status:  http.StatusInternalServerError,
+			body:    `{"error":"mount point already in use","input":{},"path":"mount/mount","status":500}`,
+			message: "mount point already in use",
+		},
+		{
+			name:    "plain text",
+			status:  http.StatusUnauthorized,
+			body:    "Unauthorized\n",
+			message: "Unauthorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewRCClient(server.URL, "", "", server.Client())
+			err := client.Unmount(context.Background(), "/mnt/source")
+
+			var rcErr *RCError
+			if !errors.As(err, &rcErr) {
+				t.Fatalf("error = %v, want *RCError", err)
+			}
+			if rcErr.Method != "mount/unmount" || rcErr.Status != tt.status || rcErr.Message != tt.message {
+				t.Errorf("RCError = %+v", *rcErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package rclone
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"rmount/config"
+)
+
+const (
+	// rcdUser rc服务的用户名，认证依赖每次启动随机生成的令牌
+	rcdUser = "rmount"
+	// rcdStartTimeout 等待rc服务开始响应的时间
+	rcdStartTimeout = 15 * time.Second
+	// rcdCheckInterval 监督循环检查rc服务和日志大小的间隔
+	rcdCheckInterval = 5 * time.Second
+	// rcdMaxBackoff 重启失败后的最长等待时间
+	rcdMaxBackoff = time.Minute
+	// rcdLogTailLines rc服务启动失败时附带的日志行数
+	rcdLogTailLines = 20
+)
+
+// rcdState 保存到 rcd.json 的rc服务连接信息，rmount重启后据此重新连接仍在运行的rc服务
+type rcdState struct {
+	PID    int    `json:"pid"`
+	Socket string `json:"socket"`
+	Token  string `json:"token"`
+}
+
+// rcDaemon 受监督的 rclone rcd 进程，所有挂载由该进程持有。
+// rmount退出时不停止它，已有挂载继续可用，下次启动时重新连接
+type rcDaemon struct {
+	configDir string
+	// logLevel 启动rc服务时使用的日志级别
+	logLevel string
+
+	mutex  sync.Mutex
+	client *RCClient
+	pid    int
+
+	superviseOnce sync.Once
+	stop          chan struct{}
+}
+
+// newRCDaemon 创建rc服务管理器，首次使用时才启动rclone
+func newRCDaemon(configDir string) *rcDaemon {
+	return &rcDaemon{
+		configDir: configDir,
+		stop:      make(chan struct{}),
+	}
+}
+
+func (d *rcDaemon) statePath() string {
+	return filepath.Join(d.configDir, "rcd.json")
+}
+
+func (d *rcDaemon) socketPath() string {
+	return filepath.Join(d.configDir, "rclone-rc.sock")
+}
+
+func (d *rcDaemon) logPath() string {
+	return RcloneLogPath(d.configDir)
+}
+
+// Client 返回可用的rc客户端，必要时连接已运行的rc服务或启动新的rc服务
+func (d *rcDaemon) Client() (*RCClient, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.superviseOnce.Do(func() {
+		go d.supervise()
+	})
+
+	if d.client != nil && d.ping(d.client) == nil {
+		return d.client, nil
+	}
+
+	if d.reconnect() {
+		return d.client, nil
+	}
+
+	if err := d.start(); err != nil {
+		return nil, err
+	}
+	return d.client, nil
+}
+
+// current 返回当前的rc客户端，不启动rc服务，尚未连接时返回nil
+func (d *rcDaemon) current() *RCClient {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.client == nil {
+		d.reconnect()
+	}
+	return d.client
+}
+
+// PID 返回rc服务进程号，未运行时返回0
+func (d *rcDaemon) PID() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.pid
+}
+
+// SetLogLevel 设置rclone日志级别。rc服务已运行时通过 options/set 立即生效，
+// 否则在启动时使用
+func (d *rcDaemon) SetLogLevel(level string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if level == d.logLevel {
+		return nil
+	}
+	d.logLevel = level
+
+	if d.client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return d.client.SetLogLevel(ctx, d.effectiveLogLevel())
+}
+
+// effectiveLogLevel 返回实际使用的日志级别，调用方需持有 mutex
+func (d *rcDaemon) effectiveLogLevel() string {
+	if d.logLevel == "" {
+		return config.LogLevelNotice
+	}
+	return d.logLevel
+}
+
+// Close 停止监督循环，rc服务及其挂载保持运行
+func (d *rcDaemon) Close() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	select {
+	case <-d.stop:
+	default:
+		close(d.stop)
+	}
+}
+
+// ping 检查rc服务是否可用
+func (d *rcDaemon) ping(client *RCClient) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return client.Noop(ctx)
+}
+
+// reconnect 按 rcd.json 连接上次启动的rc服务，调用方需持有 mutex
+func (d *rcDaemon) reconnect() bool {
+	data, err := os.ReadFile(d.statePath())
+	if err != nil {
+		return false
+	}
+
+	var state rcdState
+	if err := json.Unmarshal(data, &state); err != nil || state.Socket == "" {
+		return false
+	}
+
+	client := NewUnixRCClient(state.Socket, rcdUser, state.Token)
+	if d.ping(client) != nil {
+		return false
+	}
+
+	d.client = client
+	d.pid = state.PID
+	return true
+}
+
+// start 启动新的rc服务并等待其开始响应，调用方需持有 mutex
+func (d *rcDaemon) start() error {
+	token, err := newRCToken()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.logPath()), 0700); err != nil {
+		return fmt.Errorf("创建日志目录失败: %v", err)
+	}
+
+	socket := d.socketPath()
+	// 上次运行遗留的套接字文件会导致监听失败
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("清理rc套接字失败: %v", err)
+	}
+
+	if err := rotateLog(d.logPath()); err != nil {
+		fmt.Printf("%v\n", err)
+	}
+
+	// rclone 在解析参数之前的错误只会输出到stderr，同样追加到日志
+	logFile, err := os.OpenFile(d.logPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("创建rc服务日志失败: %v", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command("rclone", "rcd",
+		"--rc-addr", "unix://"+socket,
+		"--config", filepath.Join(d.configDir, "rclone.conf"),
+		"--cache-dir", filepath.Join(d.configDir, "cache"),
+		"--log-file", d.logPath(),
+		"--log-level", d.effectiveLogLevel(),
+	)
+	// 令牌通过环境变量传递，命令行参数对其他用户可见
+	cmd.Env = append(os.Environ(), "RCLONE_RC_USER="+rcdUser, "RCLONE_RC_PASS="+token)
+	// 输出写入文件而不是管道，rmount退出后rclone写日志不会因管道关闭而退出
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	// 独立进程组，终端发送给rmount的信号不会传给rc服务
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("启动rclone rc服务失败: %v", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	client := NewUnixRCClient(socket, rcdUser, token)
+	deadline := time.Now().Add(rcdStartTimeout)
+	for {
+		if d.ping(client) == nil {
+			break
+		}
+
+		select {
+		case err := <-exited:
+			return fmt.Errorf("rclone rc服务意外退出: %v%s", err, d.logTail())
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		if time.Now().After(deadline) {
+			cmd.Process.Kill()
+			return fmt.Errorf("rclone rc服务在 %s 内未响应%s", rcdStartTimeout, d.logTail())
+		}
+	}
+
+	state, err := json.Marshal(rcdState{PID: cmd.Process.Pid, Socket: socket, Token: token})
+	if err != nil {
+		return fmt.Errorf("序列化rc服务状态失败: %v", err)
+	}
+	if err := os.WriteFile(d.statePath(), state, 0600); err != nil {
+		return fmt.Errorf("保存rc服务状态失败: %v", err)
+	}
+
+	d.client = client
+	d.pid = cmd.Process.Pid
+	return nil
+}
+
+// supervise 定期检查rc服务，已启动过的rc服务停止响应时以指数退避重启，同时轮转rclone日志。
+// 这里只负责rc服务进程本身，rc服务退出后其挂载变为失效状态，由App的挂载监督重新挂载
+func (d *rcDaemon) supervise() {
+	ticker := time.NewTicker(rcdCheckInterval)
+	defer ticker.Stop()
+
+	backoff := time.Duration(0)
+	var nextAttempt time.Time
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if err := rotateLog(d.logPath()); err != nil {
+			fmt.Printf("%v\n", err)
+		}
+
+		d.mutex.Lock()
+		// client 为空说明还没有挂载使用过rc服务，无需监督
+		if d.client == nil {
+			d.mutex.Unlock()
+			continue
+		}
+		if d.ping(d.client) == nil {
+			backoff = 0
+			d.mutex.Unlock()
+			continue
+		}
+		if time.Now().Before(nextAttempt) {
+			d.mutex.Unlock()
+			continue
+		}
+
+		// 重启失败时保留旧的客户端，下次检查继续重试
+		fmt.Printf("rclone rc服务停止响应，正在重启\n")
+		d.pid = 0
+		err := d.start()
+		d.mutex.Unlock()
+
+		if err == nil {
+			backoff = 0
+			continue
+		}
+
+		fmt.Printf("重启rclone rc服务失败: %v\n", err)
+		if backoff == 0 {
+			backoff = rcdCheckInterval
+		} else {
+			backoff *= 2
+		}
+		if backoff > rcdMaxBackoff {
+			backoff = rcdMaxBackoff
+		}
+		nextAttempt = time.Now().Add(backoff)
+	}
+}
+
+// logTail 读取rclone日志末尾，附加到错误信息中
+func (d *rcDaemon) logTail() string {
+	lines, err := ReadLogTail(d.logPath(), rcdLogTailLines)
+	if err != nil || len(lines) == 0 {
+		return ""
+	}
+	return ", 输出: " + strings.Join(lines, "\n")
+}
+
+// newRCToken 生成rc服务的随机认证令牌
+func newRCToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成rc认证令牌失败: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -1,11 +1,14 @@
 package rclone
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -24,80 +27,262 @@ type FileInfo struct {
 
 // MountInfo 挂载信息
 type MountInfo struct {
+	// ID 挂载记录ID，同一数据源可以有多个挂载
+	ID        string `json:"id"`
+	SourceID  string `json:"sourceId"`
 	Name      string `json:"name"`
 	Remote    string `json:"remote"`
 	LocalPath string `json:"localPath"`
 	PID       int    `json:"pid"`
 	Status    string `json:"status"`
+	// ExplicitPath 挂载路径由用户指定，修改挂载根目录时不随之移动
+	ExplicitPath bool `json:"explicitPath"`
+	// AutoMount 挂载已保存为解锁后自动挂载
+	AutoMount bool `json:"autoMount"`
+	// Restarts 挂载失效后自动重新挂载的次数
+	Restarts int `json:"restarts"`
+	// LastError 最近一次失效或重新挂载失败的原因
+	LastError string `json:"lastError,omitempty"`
 }
 
+// testRemoteName 连接测试使用的临时remote名称
+const testRemoteName = "rmount-test"
+
 // RcloneManager rclone管理器
 type RcloneManager struct {
 	configDir string
+	// daemon 持有所有挂载的 rclone rcd 进程
+	daemon *rcDaemon
+	// logs 将rc服务的日志分拣到各挂载的日志文件
+	logs *logRouter
 }
 
 // NewRcloneManager 创建rclone管理器
 func NewRcloneManager(configDir string) *RcloneManager {
 	return &RcloneManager{
 		configDir: configDir,
+		daemon:    newRCDaemon(configDir),
+		logs:      newLogRouter(configDir),
 	}
 }
 
 // GenerateRcloneConfig 生成rclone配置文件
-func (rm *RcloneManager) GenerateRcloneConfig(s3Configs []config.S3Config) error {
+func (rm *RcloneManager) GenerateRcloneConfig(dataSources []config.DataSource) error {
 	configPath := filepath.Join(rm.configDir, "rclone.conf")
 	configContent := ""
 
-	for _, s3 := range s3Configs {
-		section := fmt.Sprintf("[%s]\n", s3.Name)
-		section += fmt.Sprintf("type = s3\n")
-		section += fmt.Sprintf("provider = AWS\n")
-		section += fmt.Sprintf("env_auth = false\n")
-		section += fmt.Sprintf("access_key_id = %s\n", s3.AccessKey)
-		section += fmt.Sprintf("secret_access_key = %s\n", s3.SecretKey)
-		section += fmt.Sprintf("region = %s\n", s3.Region)
-
-		if s3.Endpoint != "" {
-			section += fmt.Sprintf("endpoint = %s\n", s3.Endpoint)
-		}
-
-		if s3.Bucket != "" {
-			section += fmt.Sprintf("bucket = %s\n", s3.Bucket)
+	for _, ds := range dataSources {
+		body, err := remoteSection(ds)
+		if err != nil {
+			return fmt.Errorf("生成数据源 '%s' 的rclone配置失败: %v", ds.Name, err)
 		}
 
+		// 使用数据源ID作为remote名称，显示名称中的任意字符都不会破坏INI格式
+		section := fmt.Sprintf("[%s]\n", RemoteName(ds))
+		section += body
 		section += "\n"
 		configContent += section
 	}
 
-	return os.WriteFile(configPath, []byte(configContent), 0600)
+	// 先写临时文件再重命名，其他rmount实例或正在启动的rclone不会读到写了一半的文件
+	tmp, err := os.CreateTemp(rm.configDir, ".rclone.conf.tmp-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(configContent); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入rclone配置失败: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入rclone配置失败: %v", err)
+	}
+
+	return os.Rename(tmp.Name(), configPath)
 }
 
-// TestConnection 测试S3连接
-func (rm *RcloneManager) TestConnection(s3Config config.S3Config) error {
-	// 创建临时配置
-	tempConfig := filepath.Join(rm.configDir, "temp-test.conf")
-	defer os.Remove(tempConfig)
+// RemoveConfig 删除生成的rclone配置文件，配置锁定后不在磁盘上保留明文密钥
+func (rm *RcloneManager) RemoveConfig() error {
+	err := os.Remove(filepath.Join(rm.configDir, "rclone.conf"))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除rclone配置失败: %v", err)
+	}
+	return nil
+}
+
+// remoteSection 按数据源类型生成remote的配置项（不含节名）
+func remoteSection(ds config.DataSource) (string, error) {
+	switch {
+	case ds.Type == config.TypeS3 && ds.S3 != nil:
+		return s3Section(*ds.S3), nil
+	case ds.Type == config.TypeWebDAV && ds.WebDAV != nil:
+		return webdavSection(*ds.WebDAV)
+	case ds.Type == config.TypeSFTP && ds.SFTP != nil:
+		return sftpSection(*ds.SFTP)
+	case ds.Type == config.TypeRclone && ds.Rclone != nil:
+		return rcloneSection(*ds.Rclone), nil
+	}
+	return "", fmt.Errorf("不支持的数据源类型: %s", ds.Type)
+}
 
-	configContent := fmt.Sprintf("[%s]\ntype = s3\nprovider = AWS\nenv_auth = false\naccess_key_id = %s\nsecret_access_key = %s\nregion = %s\n",
-		s3Config.Name, s3Config.AccessKey, s3Config.SecretKey, s3Config.Region)
+// s3Section 生成S3 remote的配置项
+func s3Section(s3 config.S3Config) string {
+	provider := s3.ProviderInfo()
 
-	if s3Config.Endpoint != "" {
-		configContent += fmt.Sprintf("endpoint = %s\n", s3Config.Endpoint)
+	section := "type = s3\n"
+	section += fmt.Sprintf("provider = %s\n", provider.RcloneProvider)
+	section += s3CredentialSection(s3)
+	section += fmt.Sprintf("region = %s\n", s3.Region)
+	section += fmt.Sprintf("force_path_style = %t\n", provider.ForcePathStyle)
+
+	if endpoint := s3.ResolvedEndpoint(); endpoint != "" {
+		section += fmt.Sprintf("endpoint = %s\n", endpoint)
 	}
 
-	if s3Config.Bucket != "" {
-		configContent += fmt.Sprintf("bucket = %s\n", s3Config.Bucket)
+	return section
+}
+
+// s3CredentialSection 按凭证方式生成S3 remote的凭证配置项
+func s3CredentialSection(s3 config.S3Config) string {
+	if s3.CredentialMode == config.CredentialProfile {
+		// env_auth 让rclone通过AWS SDK读取 ~/.aws 下的命名配置
+		section := "env_auth = true\n"
+		section += fmt.Sprintf("profile = %s\n", s3.Profile)
+		return section
 	}
 
+	section := "env_auth = false\n"
+	section += fmt.Sprintf("access_key_id = %s\n", s3.AccessKey)
+	section += fmt.Sprintf("secret_access_key = %s\n", s3.SecretKey)
+
+	switch s3.CredentialMode {
+	case config.CredentialSession:
+		section += fmt.Sprintf("session_token = %s\n", s3.SessionToken)
+	case config.CredentialAssumeRole:
+		// 需要支持 role_arn 选项的rclone版本，临时凭证由rclone自行刷新
+		section += fmt.Sprintf("role_arn = %s\n", s3.RoleARN)
+		if s3.RoleSessionName != "" {
+			section += fmt.Sprintf("role_session_name = %s\n", s3.RoleSessionName)
+		} else {
+			section += "role_session_name = rmount\n"
+		}
+		if s3.ExternalID != "" {
+			section += fmt.Sprintf("role_external_id = %s\n", s3.ExternalID)
+		}
+		if s3.STSEndpoint != "" {
+			section += fmt.Sprintf("sts_endpoint = %s\n", s3.STSEndpoint)
+		}
+	}
+
+	return section
+}
+
+// webdavSection 生成WebDAV remote的配置项
+func webdavSection(c config.WebDAVConfig) (string, error) {
+	section := "type = webdav\n"
+	section += fmt.Sprintf("url = %s\n", c.URL)
+
+	vendor := c.Vendor
+	if vendor == "" {
+		vendor = "other"
+	}
+	section += fmt.Sprintf("vendor = %s\n", vendor)
+
+	if c.User != "" {
+		section += fmt.Sprintf("user = %s\n", c.User)
+	}
+
+	if c.Password != "" {
+		pass, err := Obscure(c.Password)
+		if err != nil {
+			return "", err
+		}
+		section += fmt.Sprintf("pass = %s\n", pass)
+	}
+
+	return section, nil
+}
+
+// sftpSection 生成SFTP remote的配置项
+func sftpSection(c config.SFTPConfig) (string, error) {
+	section := "type = sftp\n"
+	section += fmt.Sprintf("host = %s\n", c.Host)
+	section += fmt.Sprintf("user = %s\n", c.User)
+
+	if c.Port != 0 {
+		section += fmt.Sprintf("port = %d\n", c.Port)
+	}
+
+	if c.Password != "" {
+		pass, err := Obscure(c.Password)
+		if err != nil {
+			return "", err
+		}
+		section += fmt.Sprintf("pass = %s\n", pass)
+	}
+
+	if c.KeyFile != "" {
+		section += fmt.Sprintf("key_file = %s\n", c.KeyFile)
+	}
+
+	if c.KnownHostsFile != "" {
+		section += fmt.Sprintf("known_hosts_file = %s\n", c.KnownHostsFile)
+	}
+
+	return section, nil
+}
+
+// rcloneSection 生成任意rclone后端的配置项，选项按名称排序保证输出稳定
+func rcloneSection(c config.RcloneConfig) string {
+	section := fmt.Sprintf("type = %s\n", c.Backend)
+
+	keys := make([]string, 0, len(c.Options))
+	for key := range c.Options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		section += fmt.Sprintf("%s = %s\n", key, c.Options[key])
+	}
+
+	return section
+}
+
+// RemotePath 返回挂载或列出文件时使用的remote路径。
+// S3数据源的文件浏览以存储桶为根，挂载时需要加上存储桶前缀才能对应。
+func RemotePath(ds config.DataSource, remotePath string) string {
+	remotePath = strings.TrimPrefix(remotePath, "/")
+	if ds.Type == config.TypeS3 && ds.S3 != nil && ds.S3.Bucket != "" {
+		return path.Join(ds.S3.Bucket, remotePath)
+	}
+	return remotePath
+}
+
+// RemoteName 返回数据源在rclone配置中的remote名称
+func RemoteName(ds config.DataSource) string {
+	return ds.ID
+}
+
+// TestConnection 使用rclone测试数据源连接
+func (rm *RcloneManager) TestConnection(ds config.DataSource) error {
+	// 创建临时配置
+	tempConfig := filepath.Join(rm.configDir, "temp-test.conf")
+	defer os.Remove(tempConfig)
+
+	body, err := remoteSection(ds)
+	if err != nil {
+		return err
+	}
+	configContent := fmt.Sprintf("[%s]\n", testRemoteName) + body
+
 	if err := os.WriteFile(tempConfig, []byte(configContent), 0600); err != nil {
 		return fmt.Errorf("创建临时配置文件失败: %v", err)
 	}
 
 	// 使用rclone测试连接
-	remote := fmt.Sprintf("%s:", s3Config.Name)
-	if s3Config.Bucket != "" {
-		remote = fmt.Sprintf("%s:%s", s3Config.Name, s3Config.Bucket)
-	}
+	remote := fmt.Sprintf("%s:%s", testRemoteName, RemotePath(ds, ""))
 
 	cmd := exec.Command("rclone", "lsd", "--config", tempConfig, remote)
 	if output, err := cmd.CombinedOutput(); err != nil {
@@ -108,9 +293,9 @@ func (rm *RcloneManager) TestConnection(s3Config config.S3Config) error {
 }
 
 // ListFiles 列出文件
-func (rm *RcloneManager) ListFiles(s3Name, remotePath string) ([]FileInfo, error) {
+func (rm *RcloneManager) ListFiles(remoteName, remotePath string) ([]FileInfo, error) {
 	configPath := filepath.Join(rm.configDir, "rclone.conf")
-	remote := fmt.Sprintf("%s:%s", s3Name, remotePath)
+	remote := fmt.Sprintf("%s:%s", remoteName, remotePath)
 
 	cmd := exec.Command("rclone", "lsjson", "--config", configPath, remote)
 	output, err := cmd.Output()
@@ -126,113 +311,184 @@ func (rm *RcloneManager) ListFiles(s3Name, remotePath string) ([]FileInfo, error
 	return fileInfos, nil
 }
 
-// Mount 挂载到本地
-func (rm *RcloneManager) Mount(s3Name, remotePath, localPath string) (*exec.Cmd, error) {
+// Mount 通过rc服务的 mount/mount 挂载到本地，mountID 用于区分挂载日志
+func (rm *RcloneManager) Mount(mountID, remoteName, remotePath, localPath string, opts config.MountOptions) error {
 	// 确保本地目录存在
 	if err := os.MkdirAll(localPath, 0755); err != nil {
-		return nil, fmt.Errorf("创建挂载目录失败: %v", err)
+		return fmt.Errorf("创建挂载目录失败: %v", err)
 	}
 
-	configPath := filepath.Join(rm.configDir, "rclone.conf")
-	remote := fmt.Sprintf("%s:%s", s3Name, remotePath)
+	mountPoint := cleanMountPoint(localPath)
+	remote := fmt.Sprintf("%s:%s", remoteName, remotePath)
+	rm.logs.add(mountID, remoteName, mountPoint)
+	rm.logs.event(mountID, "挂载 %s 到 %s", remote, mountPoint)
 
-	// macOS挂载参数
-	args := []string{
-		"mount",
-		"--config", configPath,
-		"--vfs-cache-mode", "full",
-		"--cache-dir", filepath.Join(rm.configDir, "cache"),
-		"--allow-non-empty",
-		"--allow-other",
-		"--daemon",
-		remote,
-		localPath,
+	client, err := rm.daemon.Client()
+	if err != nil {
+		rm.logs.event(mountID, "%v", err)
+		return err
 	}
 
-	cmd := exec.Command("rclone", args...)
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("启动挂载失败: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), rcRequestTimeout)
+	defer cancel()
+
+	if err := client.Mount(ctx, remote, mountPoint, opts); err != nil {
+		return rm.mountFailed(mountID, err)
 	}
 
-	// 等待一段时间确保挂载成功
-	time.Sleep(2 * time.Second)
+	// mount/mount 返回时FUSE不一定已经可以访问，确认挂载点可用后才算挂载成功
+	if err := waitMountReady(mountPoint, readyTimeout(opts)); err != nil {
+		unmountCtx, unmountCancel := context.WithTimeout(context.Background(), rcRequestTimeout)
+		defer unmountCancel()
+		client.Unmount(unmountCtx, mountPoint)
 
-	// 检查进程是否还在运行
-	if cmd.ProcessState != nil && cmd.ProcessState.Exited() {
-		return nil, fmt.Errorf("挂载进程意外退出")
+		return rm.mountFailed(mountID, err)
 	}
 
-	return cmd, nil
+	rm.logs.event(mountID, "挂载已就绪")
+	return nil
 }
 
-// Unmount 卸载
-func (rm *RcloneManager) Unmount(localPath string) error {
-	// macOS使用umount命令
-	cmd := exec.Command("umount", localPath)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		// 如果umount失败，尝试使用diskutil
-		if strings.Contains(string(output), "not currently mounted") {
-			return nil // 已经卸载
-		}
+// mountFailed 记录挂载失败并附带挂载日志末尾，日志分拣在失败后停止
+func (rm *RcloneManager) mountFailed(mountID string, err error) error {
+	rm.logs.event(mountID, "挂载失败: %v", err)
+	rm.logs.remove(mountID)
+
+	tail := ""
+	if lines, readErr := ReadLogTail(rm.LogPath(mountID), mountLogTailLines); readErr == nil && len(lines) > 0 {
+		tail = ", 输出: " + strings.Join(lines, "\n")
+	}
+	return fmt.Errorf("挂载失败: %v%s", err, tail)
+}
+
+// CheckMount 检查挂载是否仍然可用，挂载已消失或FUSE连接已断开时返回错误
+func (rm *RcloneManager) CheckMount(localPath string) error {
+	return checkMountReady(cleanMountPoint(localPath))
+}
+
+// DaemonPID 返回持有挂载的rc服务进程号，未运行时返回0
+func (rm *RcloneManager) DaemonPID() int {
+	return rm.daemon.PID()
+}
+
+// Stats 返回rc服务的传输统计
+func (rm *RcloneManager) Stats() (*RCStats, error) {
+	client := rm.daemon.current()
+	if client == nil {
+		return &RCStats{}, nil
+	}
 
-		// 尝试使用diskutil卸载
-		cmd = exec.Command("diskutil", "unmount", "force", localPath)
-		if output, err := cmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("卸载失败: %v, 输出: %s", err, string(output))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return client.Stats(ctx)
+}
+
+// SetLogLevel 设置rclone日志级别，对所有挂载生效
+func (rm *RcloneManager) SetLogLevel(level string) error {
+	return rm.daemon.SetLogLevel(level)
+}
+
+// TrackMountLog 为已存在的挂载（例如rmount重启前建立的挂载）分拣日志
+func (rm *RcloneManager) TrackMountLog(mountID, remoteName, localPath string) {
+	rm.logs.add(mountID, remoteName, cleanMountPoint(localPath))
+}
+
+// LogPath 返回挂载的日志路径
+func (rm *RcloneManager) LogPath(mountID string) string {
+	rm.logs.flush()
+	return LogPath(rm.configDir, mountID)
+}
+
+// MountLog 返回挂载日志的最后 lines 行
+func (rm *RcloneManager) MountLog(mountID string, lines int) ([]string, error) {
+	if err := CheckMountID(mountID); err != nil {
+		return nil, err
+	}
+	return ReadLogTail(rm.LogPath(mountID), lines)
+}
+
+// RemoveMountLog 停止分拣并删除挂载日志及其历史文件
+func (rm *RcloneManager) RemoveMountLog(mountID string) error {
+	rm.logs.remove(mountID)
+	return removeLogFiles(LogPath(rm.configDir, mountID))
+}
+
+// MountLogIDs 返回存在日志文件的挂载ID，用于清理已不存在的挂载留下的日志
+func (rm *RcloneManager) MountLogIDs() []string {
+	files, _ := filepath.Glob(filepath.Join(rm.configDir, "logs", "*.log"))
+
+	var ids []string
+	for _, file := range files {
+		if file == RcloneLogPath(rm.configDir) {
+			continue
 		}
+		ids = append(ids, strings.TrimSuffix(filepath.Base(file), ".log"))
 	}
+	return ids
+}
 
-	return nil
+// Close 停止rc服务的监督和日志分拣，rc服务和挂载保持运行
+func (rm *RcloneManager) Close() {
+	rm.daemon.Close()
+	rm.logs.close()
+}
+
+// daemonMounts 返回rc服务持有的挂载点，rc服务未运行时返回空
+func (rm *RcloneManager) daemonMounts() map[string]bool {
+	mountPoints := make(map[string]bool)
+
+	client := rm.daemon.current()
+	if client == nil {
+		return mountPoints
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mounts, err := client.ListMounts(ctx)
+	if err != nil {
+		return mountPoints
+	}
+	for _, m := range mounts {
+		mountPoints[cleanMountPoint(m.MountPoint)] = true
+	}
+	return mountPoints
 }
 
-// GetMounts 获取当前挂载信息
+// GetMounts 从系统挂载表获取当前的rclone挂载。
+// 按挂载点而不是进程参数识别挂载，SourceID 取自 remote 名称，由调用方与自己的挂载记录匹配。
 func (rm *RcloneManager) GetMounts() ([]MountInfo, error) {
-	// 查找rclone挂载进程
-	cmd := exec.Command("pgrep", "-f", "rclone.*mount")
-	output, err := cmd.Output()
+	entries, err := ReadMountTable()
 	if err != nil {
-		return []MountInfo{}, nil // 没有挂载进程
+		return nil, err
 	}
 
-	pids := strings.Fields(string(output))
-	var mounts []MountInfo
+	daemonMounts := rm.daemonMounts()
+	daemonPID := rm.daemon.PID()
 
-	for _, pidStr := range pids {
-		// 获取进程命令行参数
-		cmd = exec.Command("ps", "-p", pidStr, "-o", "command=")
-		cmdOutput, err := cmd.Output()
-		if err != nil {
+	mounts := []MountInfo{}
+	for _, entry := range entries {
+		if entry.FSType != rcloneFSType {
 			continue
 		}
 
-		args := strings.Fields(string(cmdOutput))
-		if len(args) < 2 {
+		// remote名称即数据源ID
+		parts := strings.SplitN(entry.Source, ":", 2)
+		if len(parts) != 2 {
 			continue
 		}
 
-		// 解析挂载信息
-		var remote, localPath string
-		for i, arg := range args {
-			if arg == "mount" && i+2 < len(args) {
-				remote = args[i+2]
-				localPath = args[i+3]
-				break
-			}
+		mountInfo := MountInfo{
+			SourceID:  parts[0],
+			Remote:    entry.Source,
+			LocalPath: entry.MountPoint,
+			Status:    mountStatus(entry.MountPoint),
 		}
-
-		if remote != "" && localPath != "" {
-			// 解析数据源名称
-			parts := strings.SplitN(remote, ":", 2)
-			if len(parts) >= 1 {
-				name := parts[0]
-				mounts = append(mounts, MountInfo{
-					Name:      name,
-					Remote:    remote,
-					LocalPath: localPath,
-					Status:    "mounted",
-				})
-			}
+		// 旧版本以 --daemon 启动的挂载不属于rc服务，进程号未知
+		if daemonMounts[entry.MountPoint] {
+			mountInfo.PID = daemonPID
 		}
+		mounts = append(mounts, mountInfo)
 	}
 
 	return mounts, nil
@@ -0,0 +1,84 @@
+package rclone
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"rmount/config"
+)
+
+const (
+	// mountReadyPollInterval 检查挂载是否就绪的间隔
+	mountReadyPollInterval = 200 * time.Millisecond
+	// mountStatTimeout 单次访问挂载点的超时，FUSE无响应时 stat 会一直阻塞
+	mountStatTimeout = 5 * time.Second
+)
+
+// readyTimeout 返回挂载参数中的就绪等待时间
+func readyTimeout(opts config.MountOptions) time.Duration {
+	seconds := opts.ReadyTimeoutSeconds
+	if seconds <= 0 {
+		seconds = config.DefaultReadyTimeoutSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// waitMountReady 轮询系统挂载表并访问挂载点，直到挂载可用或超时
+func waitMountReady(mountPoint string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := checkMountReady(mountPoint)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("挂载在 %s 内未就绪: %v", timeout, err)
+		}
+		time.Sleep(mountReadyPollInterval)
+	}
+}
+
+// checkMountReady 检查挂载点已作为rclone挂载出现在挂载表中并且可以访问
+func checkMountReady(mountPoint string) error {
+	entries, err := ReadMountTable()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, entry := range entries {
+		if entry.MountPoint == mountPoint && entry.FSType == rcloneFSType {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("挂载点未出现在系统挂载表中")
+	}
+
+	return statWithTimeout(mountPoint, mountStatTimeout)
+}
+
+// errStatTimeout 访问挂载点超时，通常是FUSE进程已无响应
+var errStatTimeout = errors.New("访问挂载点超时")
+
+// statWithTimeout 访问挂载点，超时后放弃等待
+func statWithTimeout(path string, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := os.Stat(path)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("访问挂载点失败: %w", err)
+		}
+		return nil
+	case <-time.After(timeout):
+		return errStatTimeout
+	}
+}
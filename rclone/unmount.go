@@ -0,0 +1,261 @@
+package rclone
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProcessInfo 占用挂载点的进程
+type ProcessInfo struct {
+	PID  int    `json:"pid"`
+	Name string `json:"name"`
+}
+
+// MountBusyError 挂载点被进程占用，常规卸载和延迟卸载均失败
+type MountBusyError struct {
+	LocalPath string
+	Holders   []ProcessInfo
+	Output    string
+}
+
+// Error 实现error接口
+func (e *MountBusyError) Error() string {
+	if len(e.Holders) == 0 {
+		return fmt.Sprintf("挂载点 %s 正忙，无法卸载: %s", e.LocalPath, e.Output)
+	}
+
+	holders := make([]string, 0, len(e.Holders))
+	for _, p := range e.Holders {
+		holders = append(holders, fmt.Sprintf("%s(%d)", p.Name, p.PID))
+	}
+	return fmt.Sprintf("挂载点 %s 正忙，以下进程仍在使用: %s", e.LocalPath, strings.Join(holders, ", "))
+}
+
+// unmountStrategy 一种卸载方式，lazy 表示挂载点正忙时才使用的延迟卸载
+type unmountStrategy struct {
+	name string
+	args []string
+	lazy bool
+}
+
+// linuxUnmountStrategies Linux卸载链。fusermount 以普通用户身份即可卸载自己的FUSE挂载，
+// umount 通常需要root，只作为没有安装fuse工具时的兜底
+var linuxUnmountStrategies = []unmountStrategy{
+	{name: "fusermount3", args: []string{"-u"}},
+	{name: "fusermount", args: []string{"-u"}},
+	{name: "umount", args: nil},
+	{name: "fusermount3", args: []string{"-u", "-z"}, lazy: true},
+	{name: "fusermount", args: []string{"-u", "-z"}, lazy: true},
+	{name: "umount", args: []string{"-l"}, lazy: true},
+}
+
+// Unmount 卸载。rc服务持有的挂载先通过 mount/unmount 卸载，失败或挂载不属于rc服务
+// （例如rc服务已退出留下的失效挂载）时使用系统卸载命令。卸载后停止分拣该挂载的日志
+func (rm *RcloneManager) Unmount(mountID, localPath string) error {
+	mountPoint := cleanMountPoint(localPath)
+	if client := rm.daemon.current(); client != nil && rm.daemonMounts()[mountPoint] {
+		ctx, cancel := context.WithTimeout(context.Background(), rcRequestTimeout)
+		err := client.Unmount(ctx, mountPoint)
+		cancel()
+		if err == nil {
+			rm.unmounted(mountID)
+			return nil
+		}
+	}
+
+	if err := unmountSystem(localPath); err != nil {
+		return err
+	}
+	rm.unmounted(mountID)
+	return nil
+}
+
+// unmounted 记录卸载并停止分拣日志
+func (rm *RcloneManager) unmounted(mountID string) {
+	rm.logs.event(mountID, "已卸载")
+	rm.logs.remove(mountID)
+}
+
+// unmountCommand 创建卸载命令。输出需要与英文关键字比对，使用C语言环境避免翻译后的提示无法识别
+func unmountCommand(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	return cmd
+}
+
+// unmountSystem 使用系统命令卸载
+func unmountSystem(localPath string) error {
+	if runtime.GOOS == "linux" {
+		return unmountLinux(localPath)
+	}
+	return unmountDarwin(localPath)
+}
+
+// unmountDarwin macOS使用umount命令，失败后使用diskutil强制卸载
+func unmountDarwin(localPath string) error {
+	cmd := unmountCommand("umount", localPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if strings.Contains(string(output), "not currently mounted") {
+			return nil // 已经卸载
+		}
+
+		cmd = unmountCommand("diskutil", "unmount", "force", localPath)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("卸载失败: %v, 输出: %s", err, string(output))
+		}
+	}
+
+	return nil
+}
+
+// unmountLinux 依次尝试 linuxUnmountStrategies。挂载点正忙时先记录占用进程，
+// 延迟卸载会让挂载点立即从目录树分离，之后就无法再通过 /proc 找到这些进程
+func unmountLinux(localPath string) error {
+	mountPoint := cleanMountPoint(localPath)
+
+	mounted, err := isMountPoint(mountPoint)
+	if err != nil {
+		return err
+	}
+	if !mounted {
+		return nil // 已经卸载，包括rclone进程退出后被系统自动清理的情况
+	}
+
+	var (
+		busy     bool
+		holders  []ProcessInfo
+		lastErr  error
+		lastOut  string
+		attempts int
+	)
+
+	for _, s := range linuxUnmountStrategies {
+		if s.lazy && !busy {
+			break
+		}
+		if _, err := exec.LookPath(s.name); err != nil {
+			continue
+		}
+
+		attempts++
+		output, err := unmountCommand(s.name, append(s.args, mountPoint)...).CombinedOutput()
+		if err == nil {
+			return nil
+		}
+
+		out := strings.TrimSpace(string(output))
+		if mounted, checkErr := isMountPoint(mountPoint); checkErr == nil && !mounted {
+			return nil
+		}
+
+		if !busy && isBusyOutput(out) {
+			busy = true
+			holders = findMountHolders(mountPoint)
+		}
+		lastErr, lastOut = err, out
+	}
+
+	if attempts == 0 {
+		return fmt.Errorf("卸载失败: 未找到 fusermount3、fusermount 或 umount 命令")
+	}
+	if busy {
+		return &MountBusyError{LocalPath: mountPoint, Holders: holders, Output: lastOut}
+	}
+	return fmt.Errorf("卸载失败: %v, 输出: %s", lastErr, lastOut)
+}
+
+// cleanMountPoint 转换为挂载表中使用的绝对路径
+func cleanMountPoint(localPath string) string {
+	if abs, err := filepath.Abs(localPath); err == nil {
+		return abs
+	}
+	return filepath.Clean(localPath)
+}
+
+// isMountPoint 检查路径是否仍在系统挂载表中
+func isMountPoint(mountPoint string) (bool, error) {
+	entries, err := ReadMountTable()
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if entry.MountPoint == mountPoint {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isBusyOutput 判断卸载命令输出是否表示挂载点正忙 (EBUSY)
+func isBusyOutput(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "resource busy") || strings.Contains(lower, "target is busy")
+}
+
+// findMountHolders 扫描 /proc 找出工作目录、根目录或打开的文件位于挂载点下的进程。
+// 普通用户只能读取自己进程的 fd，其他用户的进程会被跳过
+func findMountHolders(mountPoint string) []ProcessInfo {
+	procEntries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+
+	var holders []ProcessInfo
+	for _, entry := range procEntries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+
+		procDir := filepath.Join("/proc", entry.Name())
+		if !processUsesPath(procDir, mountPoint) {
+			continue
+		}
+
+		name := ""
+		if comm, err := os.ReadFile(filepath.Join(procDir, "comm")); err == nil {
+			name = strings.TrimSpace(string(comm))
+		}
+		holders = append(holders, ProcessInfo{PID: pid, Name: name})
+	}
+
+	sort.Slice(holders, func(i, j int) bool {
+		return holders[i].PID < holders[j].PID
+	})
+	return holders
+}
+
+// processUsesPath 检查进程的 cwd、root、exe 和已打开的文件描述符是否位于挂载点下
+func processUsesPath(procDir, mountPoint string) bool {
+	for _, link := range []string{"cwd", "root", "exe"} {
+		if target, err := os.Readlink(filepath.Join(procDir, link)); err == nil && isUnderPath(target, mountPoint) {
+			return true
+		}
+	}
+
+	fdDir := filepath.Join(procDir, "fd")
+	fds, err := os.ReadDir(fdDir)
+	if err != nil {
+		return false
+	}
+	for _, fd := range fds {
+		if target, err := os.Readlink(filepath.Join(fdDir, fd.Name())); err == nil && isUnderPath(target, mountPoint) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnderPath 检查 target 是否为 dir 本身或其子路径
+func isUnderPath(target, dir string) bool {
+	// 已删除文件的链接目标带有 " (deleted)" 后缀
+	target = strings.TrimSuffix(target, " (deleted)")
+	return target == dir || strings.HasPrefix(target, strings.TrimSuffix(dir, "/")+"/")
+}
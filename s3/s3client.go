@@ -8,7 +8,9 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	s3config "rmount/config"
 )
 
@@ -39,26 +41,19 @@ type S3Client struct {
 // NewS3Client 创建S3客户端
 func NewS3Client(s3Config s3config.S3Config) (*S3Client, error) {
 	// 创建AWS配置
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithRegion(s3Config.Region),
-		config.WithCredentialsProvider(aws.NewCredentialsCache(
-			aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
-				return aws.Credentials{
-					AccessKeyID:     s3Config.AccessKey,
-					SecretAccessKey: s3Config.SecretKey,
-				}, nil
-			}),
-		)),
-	)
+	cfg, err := loadAWSConfig(s3Config)
 	if err != nil {
 		return nil, fmt.Errorf("创建AWS配置失败: %v", err)
 	}
 
 	// 创建S3客户端
+	// 按服务商设置Endpoint和寻址方式
+	provider := s3Config.ProviderInfo()
 	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		if s3Config.Endpoint != "" {
-			o.BaseEndpoint = aws.String(s3Config.Endpoint)
+		if endpoint := s3Config.ResolvedEndpoint(); endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
 		}
+		o.UsePathStyle = provider.ForcePathStyle
 	})
 
 	return &S3Client{
@@ -66,6 +61,83 @@ func NewS3Client(s3Config s3config.S3Config) (*S3Client, error) {
 	}, nil
 }
 
+// loadAWSConfig 按数据源的凭证方式加载AWS配置
+func loadAWSConfig(s3Config s3config.S3Config) (aws.Config, error) {
+	ctx := context.TODO()
+
+	switch s3Config.CredentialMode {
+	case s3config.CredentialProfile:
+		// 凭证完全由 ~/.aws 下的命名配置提供，包括其中的 role_arn、sso 等设置
+		return config.LoadDefaultConfig(ctx,
+			config.WithRegion(s3Config.Region),
+			config.WithSharedConfigProfile(s3Config.Profile),
+		)
+	case s3config.CredentialAssumeRole:
+		baseCfg, err := config.LoadDefaultConfig(ctx,
+			config.WithRegion(s3Config.Region),
+			config.WithCredentialsProvider(staticCredentials(s3Config.AccessKey, s3Config.SecretKey, "")),
+		)
+		if err != nil {
+			return aws.Config{}, err
+		}
+
+		stsClient := sts.NewFromConfig(baseCfg, func(o *sts.Options) {
+			if s3Config.STSEndpoint != "" {
+				o.BaseEndpoint = aws.String(s3Config.STSEndpoint)
+			}
+		})
+
+		provider := stscreds.NewAssumeRoleProvider(stsClient, s3Config.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = roleSessionName(s3Config)
+			if s3Config.ExternalID != "" {
+				o.ExternalID = aws.String(s3Config.ExternalID)
+			}
+		})
+
+		// 缓存在临时凭证过期前自动重新调用AssumeRole
+		baseCfg.Credentials = aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = credentialExpiryWindow
+		})
+		return baseCfg, nil
+	default:
+		sessionToken := ""
+		if s3Config.CredentialMode == s3config.CredentialSession {
+			sessionToken = s3Config.SessionToken
+		}
+		return config.LoadDefaultConfig(ctx,
+			config.WithRegion(s3Config.Region),
+			config.WithCredentialsProvider(staticCredentials(s3Config.AccessKey, s3Config.SecretKey, sessionToken)),
+		)
+	}
+}
+
+// credentialExpiryWindow 临时凭证在过期前多久刷新
+const credentialExpiryWindow = 5 * time.Minute
+
+// defaultRoleSessionName 未指定会话名称时使用的AssumeRole会话名
+const defaultRoleSessionName = "rmount"
+
+// roleSessionName 返回AssumeRole使用的会话名称
+func roleSessionName(s3Config s3config.S3Config) string {
+	if s3Config.RoleSessionName != "" {
+		return s3Config.RoleSessionName
+	}
+	return defaultRoleSessionName
+}
+
+// staticCredentials 返回固定凭证
+func staticCredentials(accessKey, secretKey, sessionToken string) aws.CredentialsProvider {
+	return aws.NewCredentialsCache(
+		aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{
+				AccessKeyID:     accessKey,
+				SecretAccessKey: secretKey,
+				SessionToken:    sessionToken,
+			}, nil
+		}),
+	)
+}
+
 // TestConnection 测试S3连接
 func (s *S3Client) TestConnection(bucket string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
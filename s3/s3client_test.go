@@ -0,0 +1,159 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	s3config "rmount/config"
+)
+
+// fakeSTS 记录AssumeRole请求，每次返回不同的临时凭证
+type fakeSTS struct {
+	mutex    sync.Mutex
+	requests []map[string]string
+	// lifetime 返回的临时凭证有效期
+	lifetime time.Duration
+}
+
+func (f *fakeSTS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mutex.Lock()
+	request := make(map[string]string)
+	for key := range r.PostForm {
+		request[key] = r.PostForm.Get(key)
+	}
+	f.requests = append(f.requests, request)
+	n := len(f.requests)
+	f.mutex.Unlock()
+
+	if request["Action"] != "AssumeRole" {
+		http.Error(w, "unexpected action "+request["Action"], http.StatusBadRequest)
+		return
+	}
+
+	expiration := time.Now().Add(f.lifetime).UTC().Format(time.RFC3339)
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprintf(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASIATEMP%d</AccessKeyId>
+      <SecretAccessKey>temp-secret-%d</SecretAccessKey>
+      <SessionToken>temp-token-%d</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/reader/session</Arn>
+      <AssumedRoleId>AROATEST:session</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+  <ResponseMetadata><RequestId>request-%d</RequestId></ResponseMetadata>
+</AssumeRoleResponse>`, n, n, n, expiration, n)
+}
+
+func TestAssumeRoleCredentials(t *testing.T) {
+	// 不读取本机的AWS配置
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+
+	// 有效期短于 credentialExpiryWindow，缓存每次取用时都视为即将过期
+	sts := &fakeSTS{lifetime: time.Minute}
+	server := httptest.NewServer(sts)
+	defer server.Close()
+
+	cfg, err := loadAWSConfig(s3config.S3Config{
+		Region:          "us-east-1",
+		AccessKey:       "AKIASOURCE",
+		SecretKey:       "source-secret",
+		CredentialMode:  s3config.CredentialAssumeRole,
+		RoleARN:         "arn:aws:iam::123456789012:role/reader",
+		ExternalID:      "external-123",
+		RoleSessionName: "rmount-test",
+		STSEndpoint:     server.URL,
+	})
+	if err != nil {
+		t.Fatalf("loadAWSConfig: %v", err)
+	}
+
+	first, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if first.AccessKeyID != "ASIATEMP1" || first.SessionToken != "temp-token-1" {
+		t.Errorf("first credentials = %s/%s", first.AccessKeyID, first.SessionToken)
+	}
+
+	second, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if second.AccessKeyID != "ASIATEMP2" {
+		t.Errorf("credentials were not refreshed: %s", second.AccessKeyID)
+	}
+
+	sts.mutex.Lock()
+	defer sts.mutex.Unlock()
+	if len(sts.requests) != 2 {
+		t.Fatalf("AssumeRole called %d times, want 2", len(sts.requests))
+	}
+	request := sts.requests[0]
+	if request["RoleArn"] != "arn:aws:iam::123456789012:role/reader" {
+		t.Errorf("RoleArn = %q", request["RoleArn"])
+	}
+	if request["ExternalId"] != "external-123" {
+		t.Errorf("ExternalId = %q", request["ExternalId"])
+	}
+	if request["RoleSessionName"] != "rmount-test" {
+		t.Errorf("RoleSessionName = %q", request["RoleSessionName"])
+	}
+}
+
+func TestAssumeRoleCredentialsCached(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+
+	sts := &fakeSTS{lifetime: time.Hour}
+	server := httptest.NewServer(sts)
+	defer server.Close()
+
+	cfg, err := loadAWSConfig(s3config.S3Config{
+		Region:         "us-east-1",
+		AccessKey:      "AKIASOURCE",
+		SecretKey:      "source-secret",
+		CredentialMode: s3config.CredentialAssumeRole,
+		RoleARN:        "arn:aws:iam::123456789012:role/reader",
+		STSEndpoint:    server.URL,
+	})
+	if err != nil {
+		t.Fatalf("loadAWSConfig: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := cfg.Credentials.Retrieve(context.Background()); err != nil {
+			t.Fatalf("Retrieve: %v", err)
+		}
+	}
+
+	sts.mutex.Lock()
+	defer sts.mutex.Unlock()
+	if len(sts.requests) != 1 {
+		t.Fatalf("AssumeRole called %d times, want 1", len(sts.requests))
+	}
+	if name := sts.requests[0]["RoleSessionName"]; name != defaultRoleSessionName {
+		t.Errorf("RoleSessionName = %q, want %q", name, defaultRoleSessionName)
+	}
+	if _, ok := sts.requests[0]["ExternalId"]; ok {
+		t.Error("ExternalId sent without being configured")
+	}
+}
@@ -0,0 +1,128 @@
+package sftp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"rmount/config"
+)
+
+// FileInfo 文件信息
+type FileInfo struct {
+	Name     string    `json:"Name"`
+	Path     string    `json:"Path"`
+	Size     int64     `json:"Size"`
+	ModTime  time.Time `json:"ModTime"`
+	IsDir    bool      `json:"IsDir"`
+	MimeType string    `json:"MimeType"`
+}
+
+// SFTPClient SFTP客户端，使用完毕后需要调用 Close
+type SFTPClient struct {
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+// NewSFTPClient 建立SSH连接并打开SFTP会话
+func NewSFTPClient(c config.SFTPConfig) (*SFTPClient, error) {
+	var auth []ssh.AuthMethod
+
+	if c.KeyFile != "" {
+		keyData, err := ioutil.ReadFile(c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取私钥文件失败: %v", err)
+		}
+		signer, err := ssh.ParsePrivateKey(keyData)
+		if err != nil {
+			return nil, fmt.Errorf("解析私钥失败: %v", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	if c.Password != "" {
+		auth = append(auth, ssh.Password(c.Password))
+	}
+
+	// 与rclone一致，未指定known_hosts文件时不校验主机密钥
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if c.KnownHostsFile != "" {
+		callback, err := knownhosts.New(c.KnownHostsFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取known_hosts文件失败: %v", err)
+		}
+		hostKeyCallback = callback
+	}
+
+	port := c.Port
+	if port == 0 {
+		port = 22
+	}
+
+	conn, err := ssh.Dial("tcp", net.JoinHostPort(c.Host, strconv.Itoa(port)), &ssh.ClientConfig{
+		User:            c.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         30 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SSH连接失败: %v", err)
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("打开SFTP会话失败: %v", err)
+	}
+
+	return &SFTPClient{
+		conn:   conn,
+		client: client,
+	}, nil
+}
+
+// Close 关闭连接
+func (s *SFTPClient) Close() error {
+	s.client.Close()
+	return s.conn.Close()
+}
+
+// TestConnection 测试SFTP连接
+func (s *SFTPClient) TestConnection() error {
+	if _, err := s.client.Getwd(); err != nil {
+		return fmt.Errorf("连接测试失败: %v", err)
+	}
+	return nil
+}
+
+// ListFiles 列出目录内容，相对路径以登录目录为根
+func (s *SFTPClient) ListFiles(remotePath string) ([]FileInfo, error) {
+	dir := remotePath
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := s.client.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("列出文件失败: %v", err)
+	}
+
+	var files []FileInfo
+	for _, entry := range entries {
+		files = append(files, FileInfo{
+			Name:    entry.Name(),
+			Path:    path.Join(remotePath, entry.Name()),
+			Size:    entry.Size(),
+			ModTime: entry.ModTime(),
+			IsDir:   entry.IsDir(),
+		})
+	}
+
+	return files, nil
+}
@@ -39,9 +39,11 @@ func NewGistSync(accessToken string) *GistSync {
 }
 
 // UploadToGist 上传配置到Gist
-func (gs *GistSync) UploadToGist(config *config.AppConfig, gistID string) (string, error) {
-	// 序列化配置数据
-	configData, err := json.Marshal(config)
+func (gs *GistSync) UploadToGist(appConfig *config.AppConfig, gistID string) (string, error) {
+	// 序列化配置数据。调用方只持有读锁，在副本上设置版本号
+	snapshot := *appConfig
+	snapshot.SchemaVersion = config.CurrentSchemaVersion
+	configData, err := json.Marshal(&snapshot)
 	if err != nil {
 		return "", fmt.Errorf("序列化配置失败: %v", err)
 	}
@@ -111,12 +113,13 @@ func (gs *GistSync) DownloadFromGist(gistID string) (*config.AppConfig, error) {
 			return nil, fmt.Errorf("解码配置数据失败: %v", err)
 		}
 
-		var appConfig config.AppConfig
-		if err := json.Unmarshal(configData, &appConfig); err != nil {
+		// 旧版本备份需要经过迁移链升级到当前结构
+		appConfig, err := config.ParseConfig(configData)
+		if err != nil {
 			return nil, fmt.Errorf("解析应用配置失败: %v", err)
 		}
 
-		return &appConfig, nil
+		return appConfig, nil
 	}
 
 	return nil, fmt.Errorf("Gist中未找到配置文件")
@@ -0,0 +1,181 @@
+package webdav
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"rmount/config"
+)
+
+// FileInfo 文件信息
+type FileInfo struct {
+	Name     string    `json:"Name"`
+	Path     string    `json:"Path"`
+	Size     int64     `json:"Size"`
+	ModTime  time.Time `json:"ModTime"`
+	IsDir    bool      `json:"IsDir"`
+	MimeType string    `json:"MimeType"`
+}
+
+// WebDAVClient WebDAV客户端
+type WebDAVClient struct {
+	baseURL  *url.URL
+	user     string
+	password string
+	client   *http.Client
+}
+
+// propfindBody 只请求列表需要的属性
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:">
+  <d:prop>
+    <d:resourcetype/>
+    <d:getcontentlength/>
+    <d:getlastmodified/>
+    <d:getcontenttype/>
+  </d:prop>
+</d:propfind>`
+
+// multistatus PROPFIND响应
+type multistatus struct {
+	Responses []struct {
+		Href      string `xml:"href"`
+		Propstats []struct {
+			Status string `xml:"status"`
+			Prop   struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+				ContentLength int64  `xml:"getcontentlength"`
+				LastModified  string `xml:"getlastmodified"`
+				ContentType   string `xml:"getcontenttype"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// NewWebDAVClient 创建WebDAV客户端
+func NewWebDAVClient(c config.WebDAVConfig) (*WebDAVClient, error) {
+	baseURL, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("解析WebDAV地址失败: %v", err)
+	}
+
+	// 统一以 / 结尾，方便拼接子路径
+	if !strings.HasSuffix(baseURL.Path, "/") {
+		baseURL.Path += "/"
+	}
+
+	return &WebDAVClient{
+		baseURL:  baseURL,
+		user:     c.User,
+		password: c.Password,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// TestConnection 测试WebDAV连接
+func (w *WebDAVClient) TestConnection() error {
+	if _, err := w.propfind("", "0"); err != nil {
+		return fmt.Errorf("连接测试失败: %v", err)
+	}
+	return nil
+}
+
+// ListFiles 列出目录内容
+func (w *WebDAVClient) ListFiles(remotePath string) ([]FileInfo, error) {
+	dir := strings.Trim(remotePath, "/")
+
+	result, err := w.propfind(dir, "1")
+	if err != nil {
+		return nil, fmt.Errorf("列出文件失败: %v", err)
+	}
+
+	var files []FileInfo
+	for _, resp := range result.Responses {
+		hrefURL, err := url.Parse(resp.Href)
+		if err != nil {
+			continue
+		}
+
+		// href 可能是绝对URL或绝对路径，统一转换为相对根目录的路径
+		rel := strings.TrimPrefix(hrefURL.Path, w.baseURL.Path)
+		rel = strings.Trim(rel, "/")
+		if rel == dir {
+			continue // 目录本身
+		}
+
+		for _, ps := range resp.Propstats {
+			if !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+
+			info := FileInfo{
+				Name:     path.Base(rel),
+				Path:     rel,
+				Size:     ps.Prop.ContentLength,
+				IsDir:    ps.Prop.ResourceType.Collection != nil,
+				MimeType: ps.Prop.ContentType,
+			}
+			if t, err := http.ParseTime(ps.Prop.LastModified); err == nil {
+				info.ModTime = t
+			}
+
+			files = append(files, info)
+			break
+		}
+	}
+
+	return files, nil
+}
+
+// propfind 发送PROPFIND请求
+func (w *WebDAVClient) propfind(dir, depth string) (*multistatus, error) {
+	target := *w.baseURL
+	if dir != "" {
+		target.Path = path.Join(w.baseURL.Path, dir) + "/"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", target.String(), bytes.NewBufferString(propfindBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", depth)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	if w.user != "" || w.password != "" {
+		req.SetBasicAuth(w.user, w.password)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("服务器返回 %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %v", err)
+	}
+
+	var result multistatus
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %v", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,113 @@
+package webdav
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"rmount/config"
+)
+
+// propfindResponse Nextcloud风格的响应: 目录本身、绝对URL形式的href、转义的文件名和404的属性组
+const propfindResponse = `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:">
+  <d:response>
+    <d:href>/remote.php/dav/files/alice/docs/</d:href>
+    <d:propstat>
+      <d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/remote.php/dav/files/alice/docs/my%20report.pdf</d:href>
+    <d:propstat>
+      <d:prop><d:getcontenttype/></d:prop>
+      <d:status>HTTP/1.1 404 Not Found</d:status>
+    </d:propstat>
+    <d:propstat>
+      <d:prop>
+        <d:resourcetype/>
+        <d:getcontentlength>1024</d:getcontentlength>
+        <d:getlastmodified>Mon, 02 Jan 2006 15:04:05 GMT</d:getlastmodified>
+        <d:getcontenttype>application/pdf</d:getcontenttype>
+      </d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>HOST/remote.php/dav/files/alice/docs/archive/</d:href>
+    <d:propstat>
+      <d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+</d:multistatus>`
+
+func TestListFiles(t *testing.T) {
+	var method, requestPath, depth, user, pass string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, requestPath, depth = r.Method, r.URL.Path, r.Header.Get("Depth")
+		user, pass, _ = r.BasicAuth()
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(strings.ReplaceAll(propfindResponse, "HOST", "http://"+r.Host)))
+	}))
+	defer server.Close()
+
+	client, err := NewWebDAVClient(config.WebDAVConfig{
+		URL:      server.URL + "/remote.php/dav/files/alice",
+		User:     "alice",
+		Password: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewWebDAVClient: %v", err)
+	}
+
+	files, err := client.ListFiles("/docs/")
+	if err != nil {
+		t.Fatalf("ListFiles: %v", err)
+	}
+
+	if method != "PROPFIND" || requestPath != "/remote.php/dav/files/alice/docs/" || depth != "1" {
+		t.Errorf("request = %s %s Depth %s", method, requestPath, depth)
+	}
+	if user != "alice" || pass != "secret" {
+		t.Errorf("basic auth = %q/%q", user, pass)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want 2: %+v", len(files), files)
+	}
+
+	file := files[0]
+	if file.Name != "my report.pdf" || file.Path != "docs/my report.pdf" {
+		t.Errorf("file name/path = %q/%q", file.Name, file.Path)
+	}
+	if file.IsDir || file.Size != 1024 || file.MimeType != "application/pdf" {
+		t.Errorf("file = %+v", file)
+	}
+	if want := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC); !file.ModTime.Equal(want) {
+		t.Errorf("ModTime = %v, want %v", file.ModTime, want)
+	}
+
+	dir := files[1]
+	if dir.Name != "archive" || dir.Path != "docs/archive" || !dir.IsDir {
+		t.Errorf("dir = %+v", dir)
+	}
+}
+
+func TestListFilesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client, err := NewWebDAVClient(config.WebDAVConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("NewWebDAVClient: %v", err)
+	}
+	if _, err := client.ListFiles(""); err == nil {
+		t.Fatal("ListFiles succeeded on a 401 response")
+	}
+}